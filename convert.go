@@ -0,0 +1,106 @@
+package hcl
+
+import (
+	"math"
+	"reflect"
+)
+
+// convertValue assigns a primitive value as returned by a MinDecoder to the
+// given target. Numbers are converted between the various int, uint and float
+// types, as long as the target is able to hold them, string based enums are
+// converted from strings. Pointer targets get allocated. A TypeError without
+// Address is returned if the value doesn't fit, including NaN and infinity.
+func convertValue(raw interface{}, target reflect.Value) error {
+	typeError := &TypeError{Target: target.Type(), Actual: reflect.TypeOf(raw)}
+	if raw == nil {
+		return typeError
+	}
+	vRaw := reflect.ValueOf(raw)
+	switch target.Kind() {
+	case reflect.Ptr:
+		vNew := reflect.New(target.Type().Elem())
+		if err := convertValue(raw, vNew.Elem()); err != nil {
+			return err
+		}
+		target.Set(vNew)
+		return nil
+	case reflect.Interface:
+		if !vRaw.Type().AssignableTo(target.Type()) {
+			return typeError
+		}
+		target.Set(vRaw)
+		return nil
+	case reflect.String:
+		if vRaw.Kind() != reflect.String {
+			return typeError
+		}
+		target.SetString(vRaw.String())
+		return nil
+	case reflect.Bool:
+		if vRaw.Kind() != reflect.Bool {
+			return typeError
+		}
+		target.SetBool(vRaw.Bool())
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch vRaw.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if !target.OverflowInt(vRaw.Int()) {
+				target.SetInt(vRaw.Int())
+				return nil
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if u := vRaw.Uint(); u <= math.MaxInt64 && !target.OverflowInt(int64(u)) {
+				target.SetInt(int64(u))
+				return nil
+			}
+		case reflect.Float32, reflect.Float64:
+			// float64(math.MaxInt64) rounds up to 2^63, which doesn't fit anymore
+			if f := vRaw.Float(); f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !target.OverflowInt(int64(f)) {
+				target.SetInt(int64(f))
+				return nil
+			}
+		}
+		return typeError
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch vRaw.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if i := vRaw.Int(); i >= 0 && !target.OverflowUint(uint64(i)) {
+				target.SetUint(uint64(i))
+				return nil
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if !target.OverflowUint(vRaw.Uint()) {
+				target.SetUint(vRaw.Uint())
+				return nil
+			}
+		case reflect.Float32, reflect.Float64:
+			if f := vRaw.Float(); f == math.Trunc(f) && f >= 0 && f < math.MaxUint64 && !target.OverflowUint(uint64(f)) {
+				target.SetUint(uint64(f))
+				return nil
+			}
+		}
+		return typeError
+	case reflect.Float32, reflect.Float64:
+		switch vRaw.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			target.SetFloat(float64(vRaw.Int()))
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			target.SetFloat(float64(vRaw.Uint()))
+			return nil
+		case reflect.Float32, reflect.Float64:
+			if f := vRaw.Float(); !math.IsNaN(f) && !math.IsInf(f, 0) && !target.OverflowFloat(f) {
+				target.SetFloat(f)
+				return nil
+			}
+		}
+		return typeError
+	default:
+		if vRaw.Type().AssignableTo(target.Type()) {
+			target.Set(vRaw)
+			return nil
+		}
+		return typeError
+	}
+}
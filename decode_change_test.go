@@ -0,0 +1,85 @@
+package hcl_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type changeDecoder struct {
+	testDecoder
+	Old map[string]interface{}
+	New map[string]interface{}
+}
+
+func (me *changeDecoder) GetChange(key string) (interface{}, interface{}) {
+	return me.Old[key], me.New[key]
+}
+
+func TestDecodeChange(t *testing.T) {
+	decoder := hcl.NewDecoder(&changeDecoder{
+		Old: map[string]interface{}{
+			"rule": []interface{}{map[string]interface{}{
+				"name":       "rule",
+				"kind":       "Test",
+				"conditions": []interface{}{map[string]interface{}{"key": "first", "value": 1}},
+			}},
+			"tags":  testSet{"a"},
+			"limit": 1,
+		},
+		New: map[string]interface{}{
+			"rule": []interface{}{map[string]interface{}{
+				"name": "rule",
+				"kind": "Other",
+				"conditions": []interface{}{
+					map[string]interface{}{"key": "first", "value": 2},
+					map[string]interface{}{"key": "second"},
+				},
+			}},
+			"tags":  testSet{"b", "a"},
+			"limit": 2,
+		},
+	})
+	var oldRule, newRule *Rule
+	if err := hcl.DecodeChange(decoder, "rule", &oldRule, &newRule); err != nil {
+		t.Fatal(err)
+	}
+	if oldRule == nil || oldRule.Kind != "Test" || len(oldRule.Conditions) != 1 || *oldRule.Conditions[0].Value != 1 {
+		t.Errorf("unexpected old value: %v", oldRule)
+	}
+	if newRule == nil || newRule.Kind != "Other" || len(newRule.Conditions) != 2 || newRule.Conditions[1].Key != "second" {
+		t.Errorf("unexpected new value: %v", newRule)
+	}
+
+	var oldTags, newTags []Enum
+	if err := hcl.DecodeChange(decoder, "tags", &oldTags, &newTags); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(oldTags, []Enum{"a"}) || !reflect.DeepEqual(newTags, []Enum{"b", "a"}) {
+		t.Errorf("unexpected values: %v -> %v", oldTags, newTags)
+	}
+
+	var oldLimit, newLimit int
+	if err := hcl.DecodeChange(decoder, "limit", &oldLimit, &newLimit); err != nil {
+		t.Fatal(err)
+	}
+	if oldLimit != 1 || newLimit != 2 {
+		t.Errorf("unexpected values: %v -> %v", oldLimit, newLimit)
+	}
+
+	expected := []string{"rule.0.conditions.#", "rule.0.conditions.0.value", "rule.0.conditions.1", "rule.0.kind"}
+	if keys := hcl.ChangedKeys(decoder, "rule"); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, keys)
+	}
+	// set entries are matched by hash, addressed the same way Flatten does
+	expected = []string{"tags.#"}
+	for key := range hcl.Flatten(hcl.Properties{"tags": []string{"b"}}, flatmapSchema) {
+		if key != "tags.#" {
+			expected = append(expected, key)
+		}
+	}
+	if keys := hcl.ChangedKeys(decoder, "tags"); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, keys)
+	}
+}
@@ -0,0 +1,123 @@
+package hcl_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+	"github.com/dtcookie/opt"
+)
+
+type MapContainer struct {
+	Labels     map[string]string
+	Limits     map[string]int
+	Flags      map[string]bool
+	Enums      map[string]Enum
+	Conditions map[string]*Condition
+	Empty      map[string]string
+}
+
+func (me *MapContainer) MarshalHCL() (map[string]interface{}, error) {
+	properties := hcl.Properties{}
+	return properties.EncodeAll(map[string]interface{}{
+		"labels":     me.Labels,
+		"limits":     me.Limits,
+		"flags":      me.Flags,
+		"enums":      me.Enums,
+		"conditions": me.Conditions,
+	})
+}
+
+func (me *MapContainer) UnmarshalHCL(decoder hcl.Decoder) error {
+	return decoder.DecodeAll(map[string]interface{}{
+		"labels":     &me.Labels,
+		"limits":     &me.Limits,
+		"flags":      &me.Flags,
+		"enums":      &me.Enums,
+		"conditions": &me.Conditions,
+		"empty":      &me.Empty,
+	})
+}
+
+func TestDecodeMap(t *testing.T) {
+	decoder := hcl.NewDecoder(&testDecoder{
+		Values: map[string]interface{}{
+			"labels":                 map[string]interface{}{"team": "a", "env": "prod"},
+			"labels.%":               2,
+			"limits":                 map[string]interface{}{"cpu": 2},
+			"flags":                  map[string]interface{}{"debug": true},
+			"enums":                  map[string]interface{}{"first": "Test"},
+			"conditions":             map[string]interface{}{"first": map[string]interface{}{}},
+			"conditions.first.key":   "k",
+			"conditions.first.value": 5,
+			"empty.%":                0,
+		},
+	})
+	container := &MapContainer{}
+	if err := container.UnmarshalHCL(decoder); err != nil {
+		t.Fatal(err)
+	}
+	expected := &MapContainer{
+		Labels:     map[string]string{"team": "a", "env": "prod"},
+		Limits:     map[string]int{"cpu": 2},
+		Flags:      map[string]bool{"debug": true},
+		Enums:      map[string]Enum{"first": "Test"},
+		Conditions: map[string]*Condition{"first": {Key: "k", Value: opt.NewInt(5)}},
+		Empty:      map[string]string{},
+	}
+	if !reflect.DeepEqual(container, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, container)
+	}
+
+	var limits map[string]int
+	if err := hcl.NewStrictDecoder(&testDecoder{Values: map[string]interface{}{"limits": map[string]interface{}{"cpu": "two"}}}).Decode("limits", &limits); err == nil {
+		t.Errorf("expected: %v, actual: %v", "error", err)
+	}
+}
+
+func TestEncodeMap(t *testing.T) {
+	container := &MapContainer{
+		Labels:     map[string]string{"team": "a", "env": "prod"},
+		Limits:     map[string]int{"cpu": 2},
+		Enums:      map[string]Enum{"first": "Test"},
+		Conditions: map[string]*Condition{"first": {Key: "k"}},
+	}
+	properties, err := container.MarshalHCL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"labels":     map[string]interface{}{"team": "a", "env": "prod"},
+		"limits":     map[string]interface{}{"cpu": 2},
+		"enums":      map[string]interface{}{"first": "Test"},
+		"conditions": map[string]interface{}{"first": map[string]interface{}{"key": "k"}},
+	}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, properties)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := hcl.Export(container, buf); err != nil {
+		t.Fatal(err)
+	}
+	expectedHCL := `  conditions = {
+    first = {
+      key = "k"
+    }
+  }
+  enums = {
+    first = "Test"
+  }
+  labels = {
+    env = "prod"
+    team = "a"
+  }
+  limits = {
+    cpu = 2
+  }
+`
+	if buf.String() != expectedHCL {
+		t.Errorf("expected:\n%v\nactual:\n%v", expectedHCL, buf.String())
+	}
+}
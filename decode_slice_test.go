@@ -3,6 +3,7 @@ package hcl_test
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/dtcookie/hcl"
@@ -103,3 +104,77 @@ func TestDecodeSlice(t *testing.T) {
 	}
 
 }
+
+type Priority int
+
+type valueRecord struct {
+	Value string
+}
+
+func (me *valueRecord) UnmarshalHCL(decoder hcl.Decoder) error {
+	return decoder.Decode("value", &me.Value)
+}
+
+func TestDecodeSlicePrimitives(t *testing.T) {
+	decoder := hcl.NewDecoder(&testDecoder{
+		Values: map[string]interface{}{
+			"ints":            []interface{}{1, 2},
+			"int64s":          testSet{3},
+			"bools":           []interface{}{true, false},
+			"float32s":        testSet{1.5, 2},
+			"priorities":      []interface{}{1, 2},
+			"enums":           testSet{"A"},
+			"pointers":        []interface{}{"a", "b"},
+			"strings":         []interface{}{"x"},
+			"records.#":       2,
+			"records":         []interface{}{},
+			"records.0.value": "value0",
+			"records.1.value": "value1",
+		},
+	})
+	var ints []int
+	var int64s []int64
+	var bools []bool
+	var float32s []float32
+	var priorities []Priority
+	var enums []*Enum
+	var pointers []*string
+	var recs []valueRecord
+	for key, target := range map[string]interface{}{
+		"ints":       &ints,
+		"int64s":     &int64s,
+		"bools":      &bools,
+		"float32s":   &float32s,
+		"priorities": &priorities,
+		"enums":      &enums,
+		"pointers":   &pointers,
+		"records":    &recs,
+	} {
+		if err := decoder.DecodeSlice(key, target); err != nil {
+			t.Error(err)
+		}
+	}
+	if !reflect.DeepEqual(ints, []int{1, 2}) || !reflect.DeepEqual(int64s, []int64{3}) || !reflect.DeepEqual(bools, []bool{true, false}) {
+		t.Errorf("unexpected result: %v %v %v", ints, int64s, bools)
+	}
+	if !reflect.DeepEqual(float32s, []float32{1.5, 2}) || !reflect.DeepEqual(priorities, []Priority{1, 2}) {
+		t.Errorf("unexpected result: %v %v", float32s, priorities)
+	}
+	if len(enums) != 1 || *enums[0] != "A" || len(pointers) != 2 || *pointers[1] != "b" {
+		t.Errorf("unexpected result: %v %v", enums, pointers)
+	}
+	if !reflect.DeepEqual(recs, []valueRecord{{Value: "value0"}, {Value: "value1"}}) {
+		t.Errorf("unexpected result: %v", recs)
+	}
+
+	strs := hcl.StringSet{"existing"}
+	if err := decoder.Decode("strings", &strs); err != nil {
+		t.Error(err)
+	}
+	if err := decoder.Decode("missing", &strs); err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(strs, hcl.StringSet{"x"}) {
+		t.Errorf("expected: %v, actual: %v", hcl.StringSet{"x"}, strs)
+	}
+}
@@ -0,0 +1,122 @@
+package hcl_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type Mismatch struct {
+	Length int
+}
+
+func (me *Mismatch) UnmarshalHCL(decoder hcl.Decoder) error {
+	return decoder.Decode("length", &me.Length)
+}
+
+type Panicking struct{}
+
+func (me *Panicking) UnmarshalHCL(decoder hcl.Decoder) error {
+	var value interface{} = "three"
+	_ = value.(int)
+	return nil
+}
+
+func TestDecodeStrict(t *testing.T) {
+	values := map[string]interface{}{
+		"name":            3,
+		"nested.#":        1,
+		"nested.0.length": "three",
+	}
+	{
+		var name string
+		err := hcl.NewStrictDecoder(&testDecoder{Values: values}).Decode("name", &name)
+		var typeError *hcl.TypeError
+		if !errors.As(err, &typeError) {
+			t.Fatalf("expected: %v, actual: %v", "*hcl.TypeError", err)
+		}
+		if typeError.Address != "name" || typeError.Target != reflect.TypeOf("") || typeError.Actual != reflect.TypeOf(0) {
+			t.Errorf("unexpected error: %v", typeError)
+		}
+	}
+	{
+		nested := &Mismatch{}
+		err := hcl.NewStrictDecoder(&testDecoder{Values: values}).Decode("nested", nested)
+		var typeError *hcl.TypeError
+		if !errors.As(err, &typeError) {
+			t.Fatalf("expected: %v, actual: %v", "*hcl.TypeError", err)
+		}
+		if typeError.Address != "nested.0.length" || typeError.Target != reflect.TypeOf(0) || typeError.Actual != reflect.TypeOf("") {
+			t.Errorf("unexpected error: %v", typeError)
+		}
+	}
+	{
+		var name []int
+		if err := hcl.NewDecoder(&testDecoder{Values: values}).Decode("name", &name); err != nil {
+			t.Error(err)
+		}
+		if err := hcl.NewStrictDecoder(&testDecoder{Values: values}).Decode("name", &name); err == nil {
+			t.Errorf("expected: %v, actual: %v", "error", err)
+		}
+	}
+	{
+		var name string
+		err := hcl.DecoderFrom(hcl.NewStrictDecoder(&testDecoder{Values: values})).Decode("name", &name)
+		var typeError *hcl.TypeError
+		if !errors.As(err, &typeError) {
+			t.Fatalf("expected: %v, actual: %v", "*hcl.TypeError", err)
+		}
+		if typeError.Address != "name" || typeError.Actual != reflect.TypeOf(0) {
+			t.Errorf("unexpected error: %v", typeError)
+		}
+	}
+	{
+		var kind StringEnum
+		var kindRef *StringEnum
+		for _, target := range []interface{}{&kind, &kindRef} {
+			err := hcl.NewStrictDecoder(&testDecoder{Values: values}).Decode("name", target)
+			var typeError *hcl.TypeError
+			if !errors.As(err, &typeError) {
+				t.Fatalf("expected: %v, actual: %v", "*hcl.TypeError", err)
+			}
+			if typeError.Address != "name" || typeError.Actual != reflect.TypeOf(0) {
+				t.Errorf("unexpected error: %v", typeError)
+			}
+		}
+		if kind != "" || kindRef != nil {
+			t.Errorf("expected: %v, actual: %v, %v", "untouched targets", kind, kindRef)
+		}
+	}
+	{
+		overflowing := map[string]interface{}{"small": 300, "large": 1e30, "negative": -1, "nan": math.NaN(), "inf": math.Inf(1), "huge": 1e300}
+		decoder := hcl.NewStrictDecoder(&testDecoder{Values: overflowing})
+		var i8 int8
+		var i64 int64
+		var u uint
+		var f float32
+		for _, test := range []struct {
+			key    string
+			target interface{}
+		}{{"small", &i8}, {"large", &i64}, {"negative", &u}, {"nan", &i64}, {"inf", &u}, {"huge", &f}} {
+			var typeError *hcl.TypeError
+			if err := decoder.Decode(test.key, test.target); !errors.As(err, &typeError) {
+				t.Errorf("%s: expected: %v, actual: %v", test.key, "*hcl.TypeError", err)
+			}
+		}
+		if i8 != 0 || i64 != 0 || u != 0 || f != 0 {
+			t.Errorf("expected: %v, actual: %v, %v, %v, %v", "untouched targets", i8, i64, u, f)
+		}
+	}
+	{
+		// panics within UnmarshalHCL are not for the decoder to hide
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("expected: %v, actual: %v", "panic", r)
+			}
+		}()
+		_ = hcl.NewStrictDecoder(&testDecoder{Values: values}).Decode("nested", &Panicking{})
+	}
+}
@@ -6,8 +6,7 @@ import (
 	"fmt"
 	"log"
 	"reflect"
-
-	"github.com/dtcookie/opt"
+	"sort"
 )
 
 // Decoder has no documentation
@@ -39,10 +38,13 @@ type Decoder interface {
 
 type mindecoder struct {
 	parent MinDecoder
+	strict bool
 }
 
+// DecoderFrom wraps the given MinDecoder into a Decoder.
+// If `m` is a strict Decoder, the produced Decoder is strict too.
 func DecoderFrom(m MinDecoder) Decoder {
-	return &mindecoder{parent: m}
+	return &mindecoder{parent: m, strict: isStrict(m)}
 }
 
 func (d *mindecoder) Decode(key string, v interface{}) error {
@@ -61,6 +63,39 @@ func (d *mindecoder) DecodeAny(m map[string]interface{}) (interface{}, error) {
 	return NewDecoder(d).DecodeAny(m)
 }
 
+// DecodeChange decodes the previous and the current value the given Decoder
+// stores under `key` into `old` and `new`
+func DecodeChange(d Decoder, key string, old interface{}, new interface{}) error {
+	oldValue, newValue := d.GetChange(key)
+	address := joinAddress(addressOf(d), key)
+	if err := decodeValue(address, oldValue, old); err != nil {
+		return err
+	}
+	return decodeValue(address, newValue, new)
+}
+
+// ChangedKeys lists the keys below the given prefix whose previous and current
+// values differ, e.g. `rules.0.name` or `rules.#`
+func ChangedKeys(d Decoder, prefix string) []string {
+	oldValue, newValue := d.GetChange(prefix)
+	keys := []string{}
+	walkDiff(prefix, oldValue, newValue, func(path string, _ interface{}, _ interface{}) {
+		keys = append(keys, path)
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// decodeValue decodes a raw value nested the way Properties.Encode produces it
+// into the target `v` points to. Errors are reported relative to the given address.
+func decodeValue(address string, value interface{}, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return decodeError(address, fmt.Errorf("decoding requires a non-nil pointer to store results into. %T doesn't qualify", v))
+	}
+	return unmarshalField(NewDecoder(&valueDecoder{address: address, value: value}), address, rv.Elem())
+}
+
 func (d *decoder) DecodeAny(m map[string]interface{}) (interface{}, error) {
 	if len(m) == 0 {
 		return nil, nil
@@ -68,7 +103,7 @@ func (d *decoder) DecodeAny(m map[string]interface{}) (interface{}, error) {
 	for k, v := range m {
 		found, err := d.decode(k, v)
 		if err != nil {
-			return nil, err
+			return nil, decodeError(d.path(k), err)
 		}
 		if found {
 			return v, nil
@@ -92,53 +127,136 @@ func (d *decoder) DecodeAll(m map[string]interface{}) error {
 func (d *decoder) DecodeSlice(key string, v interface{}) error {
 	rv := reflect.ValueOf(v)
 	if rv.Type().Kind() != reflect.Ptr || rv.Type().Elem().Kind() != reflect.Slice {
-		return fmt.Errorf("decoding slices requires a pointer to a slice to be specified. %T doesn't qualify", v)
+		return decodeError(d.path(key), fmt.Errorf("decoding slices requires a pointer to a slice to be specified. %T doesn't qualify", v))
 	}
 	elemType := rv.Type().Elem().Elem()
-	if !elemType.Implements(reflect.TypeOf((*Unmarshaler)(nil)).Elem()) {
-		return fmt.Errorf("decoding slices requires a pointer to a slice of elements that implement hcl.Unmarshaler to be specified. %T doesn't qualify (%v is not implementing %v)", v, elemType, reflect.TypeOf((*Unmarshaler)(nil)).Elem())
-	}
 	vSlice := rv.Elem()
-	if result, ok := d.GetOk(fmt.Sprintf("%v.#", key)); ok {
-		if untypedValue, ok := d.GetOk(key); ok {
-			setValue, ok := untypedValue.(Set)
-			if ok {
-				for _, entryMap := range setValue.List() {
-					rv := reflect.ValueOf(setValue).Elem()
-					fField := rv.FieldByName("F")
-					vhash := fField.Call([]reflect.Value{reflect.ValueOf(entryMap)})
-					hash := vhash[0].Interface()
-					entry := reflect.New(elemType.Elem()).Interface()
-					if err := entry.(Unmarshaler).UnmarshalHCL(NewDecoder(d, key, hash)); err != nil {
-						return err
-					}
-					vSlice.Set(reflect.Append(vSlice, reflect.ValueOf(entry)))
-				}
-			} else {
-				for idx := 0; idx < result.(int); idx++ {
-					entry := reflect.New(elemType.Elem()).Interface()
-					if err := entry.(Unmarshaler).UnmarshalHCL(NewDecoder(d, key, idx)); err != nil {
-						return err
-					}
-					vSlice.Set(reflect.Append(vSlice, reflect.ValueOf(entry)))
-				}
+	switch {
+	case elemType.Kind() == reflect.Ptr && elemType.Implements(unmarshalerType):
+		elemDecoders, err := elementDecoders(d, key)
+		if err != nil {
+			return err
+		}
+		for _, elemDecoder := range elemDecoders {
+			entry := reflect.New(elemType.Elem()).Interface()
+			if err := entry.(Unmarshaler).UnmarshalHCL(elemDecoder); err != nil {
+				return decodeError(addressOf(elemDecoder), err)
+			}
+			vSlice.Set(reflect.Append(vSlice, reflect.ValueOf(entry)))
+		}
+	case elemType.Kind() != reflect.Ptr && reflect.PtrTo(elemType).Implements(unmarshalerType):
+		elemDecoders, err := elementDecoders(d, key)
+		if err != nil {
+			return err
+		}
+		for _, elemDecoder := range elemDecoders {
+			entry := reflect.New(elemType)
+			if err := entry.Interface().(Unmarshaler).UnmarshalHCL(elemDecoder); err != nil {
+				return decodeError(addressOf(elemDecoder), err)
 			}
+			vSlice.Set(reflect.Append(vSlice, entry.Elem()))
 		}
+	case isStructType(elemType) || elemType.Kind() == reflect.Interface:
+		return decodeError(d.path(key), fmt.Errorf("decoding slices of structs requires elements that implement hcl.Unmarshaler. %T doesn't qualify (%v is not implementing %v)", v, elemType, unmarshalerType))
+	default:
+		_, err := d.decodeList(key, vSlice)
+		return decodeError(d.path(key), err)
 	}
-
 	return nil
 }
 
+// decodeList appends the entries of the list or set stored under the given key
+// to a slice of primitives, string or integer based enums or pointers to them.
+func (d *decoder) decodeList(key string, vSlice reflect.Value) (bool, error) {
+	result, ok := d.GetOk(key)
+	if !ok {
+		return false, nil
+	}
+	entries, ok := listOf(result)
+	if !ok {
+		if d.strict {
+			return false, &TypeError{Address: d.path(key), Target: vSlice.Type(), Actual: reflect.TypeOf(result)}
+		}
+		log.Printf("[WARN] %v %v NOT covered", vSlice.Type(), key)
+		return false, nil
+	}
+	tElem := vSlice.Type().Elem()
+	if vSlice.IsNil() {
+		vSlice.Set(reflect.MakeSlice(vSlice.Type(), 0, len(entries)))
+	}
+	for idx, entry := range entries {
+		vElem := reflect.New(tElem).Elem()
+		if err := convertValue(entry, vElem); err != nil {
+			if !d.strict {
+				log.Printf("[WARN] %v %v.%d NOT covered", tElem, key, idx)
+				continue
+			}
+			if typeError, ok := err.(*TypeError); ok {
+				typeError.Address = d.path(fmt.Sprintf("%v.%d", key, idx))
+			}
+			return true, err
+		}
+		vSlice.Set(reflect.Append(vSlice, vElem))
+	}
+	return true, nil
+}
+
+// listOf returns the entries of lists, sets and slices of any type
+func listOf(v interface{}) ([]interface{}, bool) {
+	switch tv := v.(type) {
+	case Set:
+		return tv.List(), true
+	case []interface{}:
+		return tv, true
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	entries := make([]interface{}, rv.Len())
+	for idx := range entries {
+		entries[idx] = rv.Index(idx).Interface()
+	}
+	return entries, true
+}
+
+// elementDecoders returns a Decoder for every entry of the list or set
+// stored under the given key. Entries of sets are addressed via their hash.
+func elementDecoders(d Decoder, key string) ([]Decoder, error) {
+	decoders := []Decoder{}
+	result, ok := d.GetOk(fmt.Sprintf("%v.#", key))
+	if !ok {
+		return decoders, nil
+	}
+	untypedValue, ok := d.GetOk(key)
+	if !ok {
+		return decoders, nil
+	}
+	if setValue, ok := untypedValue.(Set); ok {
+		for _, entryMap := range setValue.List() {
+			decoders = append(decoders, NewDecoder(d, key, setHash(setValue, entryMap)))
+		}
+		return decoders, nil
+	}
+	count, ok := result.(int)
+	if !ok {
+		return nil, &TypeError{Address: joinAddress(addressOf(d), key+".#"), Target: reflect.TypeOf(count), Actual: reflect.TypeOf(result)}
+	}
+	for idx := 0; idx < count; idx++ {
+		decoders = append(decoders, NewDecoder(d, key, idx))
+	}
+	return decoders, nil
+}
+
 func (d *decoder) Decode(key string, v interface{}) error {
 	_, err := d.decode(key, v)
-	return err
+	return decodeError(d.path(key), err)
 }
 
-var stringType = reflect.TypeOf("")
-var stringSliceType = reflect.TypeOf([]string{})
 var marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+var unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
 
-func (d *decoder) decode(key string, v interface{}) (bool, error) {
+func (d *decoder) decode(key string, v interface{}) (found bool, err error) {
 	vTarget := reflect.ValueOf(v)
 	if !vTarget.IsValid() || vTarget.IsNil() {
 		return false, errors.New("passed an invalid target value to Decode()")
@@ -158,8 +276,9 @@ func (d *decoder) decode(key string, v interface{}) (bool, error) {
 						if _, ok := d.GetOk(fmt.Sprintf("%v.#", key)); ok {
 							vTarget = vTarget.Elem()
 							vTarget.Set(newValue)
-							if err := unmarshaler.UnmarshalHCL(NewDecoder(d, key, 0)); err != nil {
-								return true, err
+							nested := NewDecoder(d, key, 0)
+							if err := unmarshaler.UnmarshalHCL(nested); err != nil {
+								return true, decodeError(addressOf(nested), err)
 							}
 							return true, nil
 						}
@@ -170,8 +289,9 @@ func (d *decoder) decode(key string, v interface{}) (bool, error) {
 	}
 	if unmarshaler, ok := v.(Unmarshaler); ok {
 		if _, ok := d.GetOk(fmt.Sprintf("%v.#", key)); ok {
-			if err := unmarshaler.UnmarshalHCL(NewDecoder(d, key, 0)); err != nil {
-				return true, err
+			nested := NewDecoder(d, key, 0)
+			if err := unmarshaler.UnmarshalHCL(nested); err != nil {
+				return true, decodeError(addressOf(nested), err)
 			}
 			return true, nil
 		}
@@ -179,192 +299,30 @@ func (d *decoder) decode(key string, v interface{}) (bool, error) {
 	if vTarget.Type().Kind() != reflect.Ptr {
 		return false, fmt.Errorf("Decode (%v) requires a pointer to store results into", key)
 	}
-	if result, ok := d.GetOk(key); ok {
-		switch vActual := v.(type) {
-		case *StringSet:
-			set := result.(Set)
-			strs := StringSet{}
-			for _, elem := range set.List() {
-				strs = append(strs, elem.(string))
-			}
-			*vActual = strs
-			return true, nil
-		case *[]string:
-			set := result.(Set)
-			strs := []string{}
-			for _, elem := range set.List() {
-				strs = append(strs, elem.(string))
-			}
-			*vActual = strs
-			return true, nil
-		case *[]float64:
-			set := result.(Set)
-			strs := []float64{}
-			for _, elem := range set.List() {
-				strs = append(strs, elem.(float64))
-			}
-			*vActual = strs
-			return true, nil
-		case *string:
-			*vActual = result.(string)
-			return true, nil
-		case **string:
-			*vActual = opt.NewString(result.(string))
-			return true, nil
-		case *bool:
-			*vActual = result.(bool)
-			return true, nil
-		case **bool:
-			*vActual = opt.NewBool(result.(bool))
-			return true, nil
-		case *int32:
-			*vActual = int32(result.(int))
-			return true, nil
-		case **int32:
-			*vActual = opt.NewInt32(int32(result.(int)))
-			return true, nil
-		case *int64:
-			*vActual = int64(result.(int))
-			return true, nil
-		case **int64:
-			*vActual = opt.NewInt64(int64(result.(int)))
-			return true, nil
-		case *int8:
-			*vActual = int8(result.(int))
-			return true, nil
-		case **int8:
-			*vActual = opt.NewInt8(int8(result.(int)))
-			return true, nil
-		case *int16:
-			*vActual = int16(result.(int))
-			return true, nil
-		case **int16:
-			*vActual = opt.NewInt16(int16(result.(int)))
-			return true, nil
-		case *int:
-			*vActual = int(result.(int))
-			return true, nil
-		case **int:
-			*vActual = opt.NewInt(int(result.(int)))
-			return true, nil
-		case *uint32:
-			*vActual = uint32(result.(int))
-			return true, nil
-		case **uint32:
-			*vActual = opt.NewUInt32(uint32(result.(int)))
-			return true, nil
-		case *uint64:
-			*vActual = uint64(result.(int))
-			return true, nil
-		case **uint64:
-			*vActual = opt.NewUInt64(uint64(result.(int)))
-			return true, nil
-		case *uint8:
-			*vActual = uint8(result.(int))
-			return true, nil
-		case **uint8:
-			*vActual = opt.NewUInt8(uint8(result.(int)))
-			return true, nil
-		case *uint16:
-			*vActual = uint16(result.(int))
-			return true, nil
-		case **uint16:
-			*vActual = opt.NewUInt16(uint16(result.(int)))
-			return true, nil
-		case *uint:
-			*vActual = uint(result.(int))
-			return true, nil
-		case **uint:
-			*vActual = opt.NewUint(uint(result.(int)))
-			return true, nil
-		case *float64:
-			*vActual = float64(result.(float64))
-			return true, nil
-		case **float64:
-			*vActual = opt.NewFloat64(float64(result.(float64)))
-			return true, nil
-		default:
-			vTarget := reflect.ValueOf(v)
-			tTarget := vTarget.Type()
-			if tTarget.Kind() == reflect.Ptr {
-				tElem := tTarget.Elem()
-				if tElem.Kind() == reflect.String {
-					vTarget := vTarget.Elem()
-					vResult := reflect.ValueOf(result)
-					tTarget := vTarget.Type()
-					vTarget.Set(vResult.Convert(tTarget))
-					return true, nil
-				} else if tElem.Kind() == reflect.Ptr {
-					tElem := tElem.Elem()
-					if tElem.Kind() == reflect.String {
-						vTarget := vTarget.Elem()
-						vNewEnumPtr := reflect.New(vTarget.Type().Elem())
-						vNewEnum := vNewEnumPtr.Elem()
-						valueToSet := reflect.ValueOf(result).Convert(vTarget.Type().Elem())
-						vNewEnum.Set(valueToSet)
-						vTarget.Set(vNewEnumPtr)
-						return true, nil
-					}
-				} else if tElem.Kind() == reflect.Slice {
-					tSliceElem := tElem.Elem()
-					if tSliceElem.Kind() == reflect.String {
-						enumType := tElem.Elem()
-						enumSliceType := reflect.SliceOf(enumType)
-						vEnumSlicePtr := reflect.New(enumSliceType)
-						vEnumSlice := vEnumSlicePtr.Elem()
-						for _, iString := range result.(Set).List() {
-							vEnumSlice = reflect.Append(vEnumSlice, reflect.ValueOf(iString).Convert(enumType))
-						}
-						vTarget.Elem().Set(reflect.ValueOf(vEnumSlice.Interface()))
-						return true, nil
-					}
-				}
-			}
+	if unknowns, ok := v.(*map[string]json.RawMessage); ok {
+		return d.decodeUnknowns(key, unknowns)
+	}
+	if tElem := vTarget.Type().Elem(); tElem.Kind() == reflect.Map && tElem.Key().Kind() == reflect.String {
+		return d.decodeMap(key, vTarget.Elem())
+	}
+	if tElem := vTarget.Type().Elem(); tElem.Kind() == reflect.Slice && !isStructType(tElem.Elem()) {
+		if _, ok := d.GetOk(key); ok {
+			vTarget.Elem().Set(reflect.Zero(tElem))
 		}
+		return d.decodeList(key, vTarget.Elem())
+	}
+	if result, ok := d.GetOk(key); ok {
 		vTarget := vTarget.Elem()
 		vResult := reflect.ValueOf(result)
-		tResult := vResult.Type()
-		tTarget := vTarget.Type()
-		// tOrigTarget := reflect.ValueOf(v).Type()
-		if tResult == stringType {
-			if tTarget.Kind() == reflect.String {
-				if tTarget != stringType {
-					vTarget.Set(vResult.Convert(tTarget))
-					// log.Printf("%v %v covered", tOrigTarget, key)
-					return true, nil
-				}
-			}
-			if tTarget.Kind() == reflect.Ptr {
-				tTarget = tTarget.Elem()
-				if tTarget.Kind() == reflect.String {
-					if tTarget != stringType {
-						tEnum := reflect.ValueOf(v).Type().Elem().Elem()
-						vEnumPtr := reflect.New(tEnum)
-						vEnum := vEnumPtr.Elem()
-						vEnum.Set(vResult.Convert(tEnum))
-						vTarget.Set(vEnumPtr)
-						// log.Printf("%v %v covered", tOrigTarget, key)
-						return true, nil
-					} else {
-						vTarget.Set(reflect.ValueOf(opt.NewString(result.(string))))
-						// log.Printf("%v %v covered", tOrigTarget, key)
-						return true, nil
-					}
-				}
-			}
-		}
-		setResult, ok := result.(Set)
-		if ok && vTarget.Type() == stringSliceType {
-			entries := []string{}
-			for _, entry := range setResult.List() {
-				entries = append(entries, entry.(string))
-			}
-			vTarget.Set(reflect.ValueOf(entries))
-			return true, nil
-		}
+		// convertValue only converts between kinds that are compatible, i.e. an int is
+		// never turned into a string based enum holding the rune of that int
 		if vResult.Type().AssignableTo(vTarget.Type()) {
 			vTarget.Set(vResult)
 			return true, nil
+		} else if err := convertValue(result, vTarget); err == nil {
+			return true, nil
+		} else if d.strict {
+			return false, &TypeError{Address: d.path(key), Target: reflect.ValueOf(v).Type().Elem(), Actual: vResult.Type()}
 		} else {
 			log.Printf("[WARN] %v %v NOT covered", reflect.ValueOf(v).Type(), key)
 		}
@@ -372,6 +330,82 @@ func (d *decoder) decode(key string, v interface{}) (bool, error) {
 	return false, nil
 }
 
+// decodeUnknowns restores fields unknown to the provider, which Properties.Encode
+// stores as JSON string (usually within the attribute `unknowns`)
+func (d *decoder) decodeUnknowns(key string, unknowns *map[string]json.RawMessage) (bool, error) {
+	result, ok := d.GetOk(key)
+	if !ok {
+		return false, nil
+	}
+	data, ok := result.(string)
+	if !ok {
+		if d.strict {
+			return false, &TypeError{Address: d.path(key), Target: reflect.TypeOf(unknowns).Elem(), Actual: reflect.TypeOf(result)}
+		}
+		log.Printf("[WARN] %v %v NOT covered", reflect.TypeOf(unknowns), key)
+		return false, nil
+	}
+	if len(data) == 0 {
+		return false, nil
+	}
+	restored := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(data), &restored); err != nil {
+		return false, err
+	}
+	*unknowns = restored
+	return true, nil
+}
+
+// decodeMap populates a map with string keys from the attribute stored under the given key.
+// Maps of primitives are converted entry by entry, maps of structs (or pointers to structs)
+// get their entries decoded from `key.<name>.` via unmarshal.
+func (d *decoder) decodeMap(key string, vTarget reflect.Value) (bool, error) {
+	result, ok := d.GetOk(key)
+	if !ok {
+		if count, ok := d.GetOk(fmt.Sprintf("%v.%%", key)); ok && count == 0 {
+			vTarget.Set(reflect.MakeMap(vTarget.Type()))
+			return true, nil
+		}
+		return false, nil
+	}
+	entries, ok := result.(map[string]interface{})
+	if !ok {
+		if d.strict {
+			return false, &TypeError{Address: d.path(key), Target: vTarget.Type(), Actual: reflect.TypeOf(result)}
+		}
+		log.Printf("[WARN] %v %v NOT covered", vTarget.Type(), key)
+		return false, nil
+	}
+	tMap := vTarget.Type()
+	tElem := tMap.Elem()
+	vMap := reflect.MakeMapWithSize(tMap, len(entries))
+	for name, entry := range entries {
+		vElem := reflect.New(tElem).Elem()
+		if isStructType(tElem) {
+			vStruct := vElem.Addr()
+			if tElem.Kind() == reflect.Ptr {
+				vElem.Set(reflect.New(tElem.Elem()))
+				vStruct = vElem
+			}
+			if err := unmarshal(NewDecoder(d, key, name), vStruct.Interface()); err != nil {
+				return true, err
+			}
+		} else if err := convertValue(entry, vElem); err != nil {
+			if !d.strict {
+				log.Printf("[WARN] %v %v.%v NOT covered", tElem, key, name)
+				continue
+			}
+			if typeError, ok := err.(*TypeError); ok {
+				typeError.Address = d.path(key + "." + name)
+			}
+			return true, err
+		}
+		vMap.SetMapIndex(reflect.ValueOf(name).Convert(tMap.Key()), vElem)
+	}
+	vTarget.Set(vMap)
+	return true, nil
+}
+
 func (d *mindecoder) GetStringSet(key string) []string {
 	result := []string{}
 	if value, ok := d.GetOk(key); ok {
@@ -433,12 +467,65 @@ func NewDecoder(parent MinDecoder, address ...interface{}) Decoder {
 		joined = joined + sep + fmt.Sprintf("%v", part)
 		sep = "."
 	}
-	return &decoder{parent: parent, address: joined}
+	return &decoder{parent: parent, address: joined, strict: isStrict(parent)}
+}
+
+// NewStrictDecoder produces a Decoder which reports values that cannot get assigned
+// to the target passed to Decode as TypeError instead of logging a warning and leaving
+// the target untouched. Decoders derived from it via NewDecoder are strict too.
+func NewStrictDecoder(parent MinDecoder, address ...interface{}) Decoder {
+	d := NewDecoder(parent, address...).(*decoder)
+	d.strict = true
+	return d
+}
+
+// isStrict reports whether the given MinDecoder or any of the decoders
+// it is wrapping has been created via NewStrictDecoder
+func isStrict(m MinDecoder) bool {
+	switch d := m.(type) {
+	case *decoder:
+		return d.strict
+	case *mindecoder:
+		return d.strict
+	case *schemaDecoder:
+		return isStrict(d.parent)
+	default:
+		return false
+	}
 }
 
 type decoder struct {
 	parent  MinDecoder
 	address string
+	strict  bool
+}
+
+// path returns the full address of the given key, including the
+// addresses of all parent decoders
+func (d *decoder) path(key string) string {
+	return joinAddress(addressOf(d), key)
+}
+
+// addressOf returns the full address the given decoder is reading from
+func addressOf(m MinDecoder) string {
+	switch d := m.(type) {
+	case *decoder:
+		return joinAddress(addressOf(d.parent), d.address)
+	case *mindecoder:
+		return addressOf(d.parent)
+	default:
+		return ""
+	}
+}
+
+func joinAddress(address string, key string) string {
+	if address == "" {
+		return key
+	}
+	if key == "" {
+		return address
+	}
+	return address + "." + key
 }
 
 func (d *decoder) Reader(unkowns ...map[string]json.RawMessage) Reader {
@@ -0,0 +1,104 @@
+package hcl_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type Legacy struct {
+	Name     string   `hcl:"name"`
+	Host     string   `hcl:"host,omitempty" deprecated:"use endpoint instead"`
+	Endpoint string   `hcl:"endpoint,omitempty"`
+	Ports    []*Port  `hcl:"ports,omitempty"`
+	Aliases  []string `hcl:"aliases,omitempty" deprecated:"aliases are ignored"`
+}
+
+type Port struct {
+	Number   int    `hcl:"number"`
+	Protocol string `hcl:"protocol,omitempty" deprecated:"the protocol is detected automatically"`
+}
+
+func TestDeprecatedWarnings(t *testing.T) {
+	properties := hcl.Properties{
+		"name":    "legacy",
+		"host":    "localhost",
+		"aliases": []string{"a"},
+		"ports": []interface{}{
+			map[string]interface{}{"number": 80},
+			map[string]interface{}{"number": 443, "protocol": "https"},
+		},
+	}
+	schema, err := hcl.SchemaOf(&Legacy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := hcl.NewSchemaDecoder(hcl.PropertiesDecoder(properties), schema)
+	var legacy Legacy
+	if err := decoder.Reader().Decode(&legacy); err != nil {
+		t.Fatal(err)
+	}
+	expected := hcl.Diagnostics{
+		{Severity: hcl.SeverityWarning, Path: "host", Summary: "use endpoint instead"},
+		{Severity: hcl.SeverityWarning, Path: "ports.1.protocol", Summary: "the protocol is detected automatically"},
+		{Severity: hcl.SeverityWarning, Path: "aliases", Summary: "aliases are ignored"},
+	}
+	diags := hcl.DiagnosticsOf(decoder)
+	if !reflect.DeepEqual(diags, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, diags)
+	}
+	if diags.HasError() {
+		t.Errorf("expected: %v, actual: %v", false, diags.HasError())
+	}
+
+	// deprecated attributes are reported even if nobody reads them
+	decoder = hcl.NewSchemaDecoder(hcl.PropertiesDecoder(properties), schema)
+	var name string
+	if err := decoder.Decode("name", &name); err != nil {
+		t.Fatal(err)
+	}
+	expected = hcl.Diagnostics{
+		{Severity: hcl.SeverityWarning, Path: "aliases", Summary: "aliases are ignored"},
+		{Severity: hcl.SeverityWarning, Path: "host", Summary: "use endpoint instead"},
+		{Severity: hcl.SeverityWarning, Path: "ports.1.protocol", Summary: "the protocol is detected automatically"},
+	}
+	if diags := hcl.DiagnosticsOf(decoder); !reflect.DeepEqual(diags, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, diags)
+	}
+}
+
+func TestExportDeprecated(t *testing.T) {
+	legacy := &Legacy{Name: "legacy", Host: "localhost", Ports: []*Port{{Number: 443, Protocol: "https"}}}
+
+	buf := new(bytes.Buffer)
+	if err := hcl.ExportOpt(hcl.AsMarshaler(legacy), buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `  name = "legacy" 
+  # deprecated: use endpoint instead
+  host = "localhost" 
+  ports {
+    number = 443 
+    # deprecated: the protocol is detected automatically
+    protocol = "https" 
+  }
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+
+	buf = new(bytes.Buffer)
+	if err := hcl.ExportOpt(hcl.AsMarshaler(legacy), buf, &hcl.ExportOptions{Deprecated: hcl.DeprecatedSkip}); err != nil {
+		t.Fatal(err)
+	}
+	expected = `  name = "legacy" 
+  ports {
+    number = 443 
+  }
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+}
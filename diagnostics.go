@@ -0,0 +1,68 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Severity distinguishes errors from warnings
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Diagnostic reports a problem with the attribute at the given path
+type Diagnostic struct {
+	Severity Severity
+	Path     string
+	Summary  string
+}
+
+func (d Diagnostic) String() string {
+	if d.Path == "" {
+		return fmt.Sprintf("%v: %s", d.Severity, d.Summary)
+	}
+	return fmt.Sprintf("%v: %s: %s", d.Severity, d.Path, d.Summary)
+}
+
+// Diagnostics is a list of errors and warnings
+type Diagnostics []Diagnostic
+
+// HasError reports whether any of the diagnostics is an error
+func (d Diagnostics) HasError() bool {
+	for _, diag := range d {
+		if diag.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Err combines all errors into a single error, or returns nil if there are none.
+// Warnings are ignored.
+func (d Diagnostics) Err() error {
+	msgs := []string{}
+	for _, diag := range d {
+		if diag.Severity == SeverityError {
+			msgs = append(msgs, diag.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(msgs, "\n"))
+}
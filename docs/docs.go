@@ -0,0 +1,203 @@
+// Package docs renders Terraform registry style reference documentation
+// in Markdown based on the schema of a resource.
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dtcookie/hcl"
+)
+
+// Options customize the produced documentation
+type Options struct {
+	// Description is rendered right below the title of the page
+	Description string
+	// Kind is either `Resource` (the default) or `Data Source`
+	Kind string
+	// Example, if specified, gets exported via hcl.ExportResource and rendered as `Example Usage`
+	Example hcl.Marshaler
+}
+
+// Write renders the documentation of the resource `name` described by the given Schemer.
+//   - `Argument Reference` lists all required and optional attributes
+//   - every block gets its own section `Nested Schema for ...`, linked via the anchor `nestedblock--<path>`
+//   - `Attributes Reference` lists the attributes which are only `Computed`
+//
+// Descriptions, deprecation notes and defaults are taken from the schema.
+func Write(w io.Writer, name string, schemer hcl.Schemer, opts ...*Options) error {
+	options := &Options{}
+	if len(opts) > 0 && opts[0] != nil {
+		options = opts[0]
+	}
+	kind := options.Kind
+	if kind == "" {
+		kind = "Resource"
+	}
+	p := &printer{}
+	p.printf("---\npage_title: \"%s %s\"\n---\n\n", name, kind)
+	p.printf("# %s (%s)\n\n", name, kind)
+	if options.Description != "" {
+		p.printf("%s\n\n", options.Description)
+	}
+	if options.Example != nil {
+		buf := new(bytes.Buffer)
+		if err := hcl.ExportResource(buf, name, "example", options.Example, &hcl.ResourceOptions{Data: kind == "Data Source"}); err != nil {
+			return err
+		}
+		p.printf("## Example Usage\n\n```terraform\n%s```\n\n", buf.String())
+	}
+	schema := schemer.Schema()
+	p.printf("## Argument Reference\n\nThe following arguments are supported:\n\n")
+	p.arguments("", schema)
+	if computed := filter(schema, isReadOnly); len(computed) > 0 {
+		p.printf("\n## Attributes Reference\n\nIn addition to all arguments above, the following attributes are exported:\n\n")
+		for _, name := range computed {
+			p.attribute("", name, schema[name])
+		}
+	}
+	p.blocks("", schema)
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+type printer struct {
+	buf bytes.Buffer
+}
+
+func (p *printer) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&p.buf, format, args...)
+}
+
+// arguments lists the required attributes first, followed by the optional ones
+func (p *printer) arguments(path string, schema map[string]*hcl.Schema) {
+	for _, name := range filter(schema, func(sch *hcl.Schema) bool { return sch.Required }) {
+		p.attribute(path, name, schema[name])
+	}
+	for _, name := range filter(schema, func(sch *hcl.Schema) bool { return !sch.Required && !isReadOnly(sch) }) {
+		p.attribute(path, name, schema[name])
+	}
+}
+
+func (p *printer) attribute(path string, name string, sch *hcl.Schema) {
+	qualifiers := []string{}
+	switch {
+	case sch.Required:
+		qualifiers = append(qualifiers, "Required")
+	case !isReadOnly(sch):
+		qualifiers = append(qualifiers, "Optional")
+	}
+	qualifiers = append(qualifiers, typeName(sch))
+	if sch.MaxItems > 0 && isBlock(sch) {
+		qualifiers = append(qualifiers, fmt.Sprintf("Max: %d", sch.MaxItems))
+	}
+	if sch.Deprecated != "" {
+		qualifiers = append(qualifiers, "Deprecated")
+	}
+	line := fmt.Sprintf("- `%s` (%s)", name, strings.Join(qualifiers, ", "))
+	if sch.Deprecated != "" {
+		line += " " + sentence(sch.Deprecated)
+	}
+	if sch.Description != "" {
+		line += " " + sentence(sch.Description)
+	}
+	if sch.Default != nil {
+		if data, err := json.Marshal(sch.Default); err == nil {
+			line += fmt.Sprintf(" Defaults to `%s`.", string(data))
+		}
+	}
+	if sch.Sensitive {
+		line += " This value is sensitive."
+	}
+	if isBlock(sch) {
+		line += fmt.Sprintf(" See [below for nested schema](#%s).", anchor(path, name))
+	}
+	p.printf("%s\n", line)
+}
+
+// blocks renders a section for every block, recursing into nested blocks
+func (p *printer) blocks(path string, schema map[string]*hcl.Schema) {
+	for _, name := range filter(schema, isBlock) {
+		resource := schema[name].Elem.(*hcl.Resource)
+		p.printf("\n<a id=\"%s\"></a>\n### Nested Schema for `%s`\n\n", anchor(path, name), joinPath(path, name))
+		if len(filter(resource.Schema, func(sch *hcl.Schema) bool { return !isReadOnly(sch) })) > 0 {
+			p.arguments(joinPath(path, name), resource.Schema)
+		}
+		if computed := filter(resource.Schema, isReadOnly); len(computed) > 0 {
+			p.printf("\nRead-Only:\n\n")
+			for _, computedName := range computed {
+				p.attribute(joinPath(path, name), computedName, resource.Schema[computedName])
+			}
+		}
+		p.blocks(joinPath(path, name), resource.Schema)
+	}
+}
+
+// filter returns the sorted names of the attributes matching the given predicate
+func filter(schema map[string]*hcl.Schema, predicate func(sch *hcl.Schema) bool) []string {
+	names := []string{}
+	for name, sch := range schema {
+		if sch != nil && predicate(sch) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isReadOnly(sch *hcl.Schema) bool {
+	return sch.Computed && !sch.Optional && !sch.Required
+}
+
+func isBlock(sch *hcl.Schema) bool {
+	if sch.Type != hcl.TypeList && sch.Type != hcl.TypeSet {
+		return false
+	}
+	_, ok := sch.Elem.(*hcl.Resource)
+	return ok
+}
+
+func typeName(sch *hcl.Schema) string {
+	switch sch.Type {
+	case hcl.TypeBool:
+		return "Boolean"
+	case hcl.TypeInt, hcl.TypeFloat:
+		return "Number"
+	case hcl.TypeString:
+		return "String"
+	case hcl.TypeList, hcl.TypeSet, hcl.TypeMap:
+		collection := map[hcl.ValueType]string{hcl.TypeList: "List", hcl.TypeSet: "Set", hcl.TypeMap: "Map"}[sch.Type]
+		switch elem := sch.Elem.(type) {
+		case *hcl.Resource:
+			return "Block " + collection
+		case *hcl.Schema:
+			return collection + " of " + typeName(elem)
+		}
+		return collection
+	}
+	return "Unknown"
+}
+
+func anchor(path string, name string) string {
+	return "nestedblock--" + strings.ReplaceAll(joinPath(path, name), ".", "--")
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// sentence makes sure the given text ends with a period
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, ".") {
+		return s
+	}
+	return s + "."
+}
@@ -0,0 +1,84 @@
+package docs_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+	"github.com/dtcookie/hcl/docs"
+)
+
+type Rule struct {
+	Key   string `hcl:"key" description:"The key to match"`
+	Value *int   `hcl:"value" description:"The value to match"`
+}
+
+type Alert struct {
+	Name    string  `hcl:"name" description:"The name of the alert"`
+	Enabled bool    `hcl:"enabled,omitempty" description:"Whether the alert is active"`
+	Token   string  `hcl:"token,omitempty,sensitive"`
+	Legacy  string  `hcl:"legacy,omitempty" deprecated:"Use name instead"`
+	ID      string  `hcl:"id,computed,omitempty" description:"The ID of the alert"`
+	Rules   []*Rule `hcl:"rules,maxitems=2"`
+}
+
+func (me *Alert) Schema() map[string]*hcl.Schema {
+	schema, _ := hcl.SchemaOf(me)
+	schema["enabled"].Default = true
+	return schema
+}
+
+func TestWrite(t *testing.T) {
+	value := 2
+	alert := &Alert{Name: "alert", Enabled: true, Rules: []*Rule{{Key: "a", Value: &value}}}
+	buf := new(bytes.Buffer)
+	if err := docs.Write(buf, "example_alert", alert, &docs.Options{Description: "Manages alerts.", Example: hcl.AsMarshaler(alert)}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `---
+page_title: "example_alert Resource"
+---
+
+# example_alert (Resource)
+
+Manages alerts.
+
+## Example Usage
+
+` + "```" + `terraform
+resource "example_alert" "example" {
+  name = "alert" 
+  enabled = true 
+  rules {
+    key = "a" 
+    value = 2 
+  }
+}
+` + "```" + `
+
+## Argument Reference
+
+The following arguments are supported:
+
+- ` + "`name`" + ` (Required, String) The name of the alert.
+- ` + "`enabled`" + ` (Optional, Boolean) Whether the alert is active. Defaults to ` + "`true`" + `.
+- ` + "`legacy`" + ` (Optional, String, Deprecated) Use name instead.
+- ` + "`rules`" + ` (Optional, Block List, Max: 2) See [below for nested schema](#nestedblock--rules).
+- ` + "`token`" + ` (Optional, String) This value is sensitive.
+
+## Attributes Reference
+
+In addition to all arguments above, the following attributes are exported:
+
+- ` + "`id`" + ` (String) The ID of the alert.
+
+<a id="nestedblock--rules"></a>
+### Nested Schema for ` + "`rules`" + `
+
+- ` + "`key`" + ` (Required, String) The key to match.
+- ` + "`value`" + ` (Optional, Number) The value to match.
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+}
@@ -0,0 +1,83 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// TypeError is returned by strict decoders in case the value found for an
+// attribute cannot get assigned to the target passed to Decode
+type TypeError struct {
+	Address string
+	Target  reflect.Type
+	Actual  reflect.Type
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("cannot decode value of type %v into %v", e.Actual, e.Target)
+}
+
+// DecodeError wraps any error that occurred while decoding the attribute
+// at the given address. The address is always the full path of the attribute,
+// e.g. `rules.3.conditions.0.value`, regardless of how deeply nested
+// the Decoder reporting the error has been.
+type DecodeError struct {
+	Address string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Address == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Address, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeError wraps the given error into a DecodeError, unless it
+// already carries the address it originated from
+func decodeError(address string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		return err
+	}
+	return &DecodeError{Address: address, Err: err}
+}
+
+// EncodeError wraps any error that occurred while encoding the attribute
+// at the given address. Errors of nested Marshalers get prefixed with the
+// address of the block they are encoded into, e.g. `rules.3.conditions.0.value`.
+type EncodeError struct {
+	Address string
+	Err     error
+}
+
+func (e *EncodeError) Error() string {
+	if e.Address == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Address, e.Err)
+}
+
+func (e *EncodeError) Unwrap() error {
+	return e.Err
+}
+
+// encodeError wraps the given error into an EncodeError. If it already
+// is an EncodeError, its address gets prefixed with the given one.
+func encodeError(address string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if encodeErr, ok := err.(*EncodeError); ok {
+		return &EncodeError{Address: joinAddress(address, encodeErr.Address), Err: encodeErr.Err}
+	}
+	return &EncodeError{Address: address, Err: err}
+}
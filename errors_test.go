@@ -0,0 +1,110 @@
+package hcl_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+var errInvalid = errors.New("invalid")
+
+type failingCondition struct {
+	Value int
+	Fail  bool
+}
+
+func (me *failingCondition) MarshalHCL() (map[string]interface{}, error) {
+	if me.Fail {
+		return nil, errInvalid
+	}
+	return hcl.Properties{"value": me.Value}, nil
+}
+
+func (me *failingCondition) UnmarshalHCL(decoder hcl.Decoder) error {
+	if err := decoder.Decode("value", &me.Value); err != nil {
+		return err
+	}
+	if me.Value < 0 {
+		return errInvalid
+	}
+	return nil
+}
+
+type failingRule struct {
+	Conditions []*failingCondition
+}
+
+func (me *failingRule) MarshalHCL() (map[string]interface{}, error) {
+	properties := hcl.Properties{}
+	return properties.EncodeSlice("conditions", me.Conditions)
+}
+
+func (me *failingRule) UnmarshalHCL(decoder hcl.Decoder) error {
+	return decoder.DecodeSlice("conditions", &me.Conditions)
+}
+
+type failingRules struct {
+	Rules []*failingRule
+}
+
+func (me *failingRules) UnmarshalHCL(decoder hcl.Decoder) error {
+	return decoder.DecodeSlice("rules", &me.Rules)
+}
+
+func TestDecodeError(t *testing.T) {
+	values := map[string]interface{}{
+		"rules.#":                      4,
+		"rules":                        []interface{}{},
+		"rules.3.conditions.#":         1,
+		"rules.3.conditions":           []interface{}{},
+		"rules.3.conditions.0.value":   "x",
+		"rules.2.conditions.#":         2,
+		"rules.2.conditions":           []interface{}{},
+		"rules.2.conditions.1.value":   -1,
+		"rules.2.conditions.0.value":   1,
+		"rules.0.conditions.#":         0,
+		"rules.1.conditions.#":         0,
+		"rules.1.conditions.0.ignored": 0,
+	}
+	{
+		err := (&failingRules{}).UnmarshalHCL(hcl.NewDecoder(&testDecoder{Values: values}))
+		var decodeError *hcl.DecodeError
+		if !errors.As(err, &decodeError) {
+			t.Fatalf("expected: %v, actual: %v", "*hcl.DecodeError", err)
+		}
+		if decodeError.Address != "rules.2.conditions.1" || !errors.Is(err, errInvalid) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	{
+		delete(values, "rules.2.conditions.#")
+		err := (&failingRules{}).UnmarshalHCL(hcl.NewStrictDecoder(&testDecoder{Values: values}))
+		var decodeError *hcl.DecodeError
+		if !errors.As(err, &decodeError) {
+			t.Fatalf("expected: %v, actual: %v", "*hcl.DecodeError", err)
+		}
+		var typeError *hcl.TypeError
+		if decodeError.Address != "rules.3.conditions.0.value" || !errors.As(err, &typeError) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err.Error() != "rules.3.conditions.0.value: cannot decode value of type string into int" {
+			t.Errorf("unexpected error message: %v", err)
+		}
+	}
+}
+
+func TestEncodeError(t *testing.T) {
+	rules := []*failingRule{
+		{Conditions: []*failingCondition{{Value: 1}}},
+		{Conditions: []*failingCondition{{Value: 1}, {Fail: true}}},
+	}
+	_, err := hcl.Properties{}.EncodeSlice("rules", rules)
+	var encodeError *hcl.EncodeError
+	if !errors.As(err, &encodeError) {
+		t.Fatalf("expected: %v, actual: %v", "*hcl.EncodeError", err)
+	}
+	if encodeError.Address != "rules.1.conditions.1" || !errors.Is(err, errInvalid) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,9 +1,9 @@
 package hcl
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
 	"sort"
 	"strings"
@@ -26,7 +26,7 @@ func sk(s string) string {
 }
 
 type exportEntry interface {
-	Write(w io.Writer, indent string) error
+	Write(w io.Writer, indent string, layout *exportLayout) error
 	IsOptional() bool
 	IsDefault() bool
 	IsLessThan(other exportEntry) bool
@@ -62,7 +62,7 @@ func resOpt0(key string, bc string, sch *Schema) bool {
 		}
 
 	case TypeMap:
-		return false
+		return sch.Optional
 	case TypeSet:
 		return false
 	default:
@@ -85,7 +85,7 @@ func (e *exportEntries) eval(key string, value interface{}, breadCrumbs string,
 		return
 	}
 	switch v := value.(type) {
-	case string, bool, int, int32, int64, int8, int16, uint, uint32, uint64, uint8, uint16, float32, float64:
+	case string, bool, int, int32, int64, int8, int16, uint, uint32, uint64, uint8, uint16, float32, float64, Expr:
 		entry := &primitiveEntry{Key: key, Value: value, BreadCrumbs: breadCrumbs, Optional: resOpt(breadCrumbs, schema)}
 		*e = append(*e, entry)
 	case *string, *bool, *int, *int32, *int64, *int8, *int16, *uint, *uint32, *uint64, *uint8, *uint16, *float32, *float64:
@@ -101,11 +101,11 @@ func (e *exportEntries) eval(key string, value interface{}, breadCrumbs string,
 		switch typedElem := v[0].(type) {
 		case map[string]interface{}:
 			for _, elem := range v {
-				entry := &resourceEntry{Key: key, Entries: exportEntries{}}
+				entry := &resourceEntry{Key: key, Entries: exportEntries{}, Schema: elemSchema(schemaAt(schema, crumbsToKey(breadCrumbs)))}
 				entry.Entries.handle(elem.(map[string]interface{}), breadCrumbs, schema)
 				*e = append(*e, entry)
 			}
-		case string, bool, int, int32, int64, int8, int16, uint, uint32, uint64, uint8, uint16, float32, float64:
+		case string, bool, int, int32, int64, int8, int16, uint, uint32, uint64, uint8, uint16, float32, float64, Expr:
 			entry := &primitiveEntry{Key: key, Value: value}
 			*e = append(*e, entry)
 		default:
@@ -133,10 +133,7 @@ func (e *exportEntries) eval(key string, value interface{}, breadCrumbs string,
 		if len(v) == 0 {
 			return
 		}
-		entry := &resourceEntry{Key: key, Entries: exportEntries{}}
-		for xk, xv := range v {
-			entry.Entries = append(entry.Entries, &primitiveEntry{Key: xk, Value: xv, Optional: resOpt(breadCrumbs, schema)})
-		}
+		entry := &mapEntry{Key: key, Value: v, Optional: resOpt(breadCrumbs, schema)}
 		*e = append(*e, entry)
 	default:
 		rv := reflect.ValueOf(v)
@@ -152,15 +149,289 @@ func (e *exportEntries) eval(key string, value interface{}, breadCrumbs string,
 
 func (e *exportEntries) handle(m map[string]interface{}, breadCrumbs string, schema map[string]*Schema) {
 	for k, v := range m {
+		n := len(*e)
 		e.eval(k, v, breadCrumbs+"."+k, schema)
+		if sch := schemaAt(schema, crumbsToKey(breadCrumbs+"."+k)); sch != nil && sch.Deprecated != "" {
+			for _, entry := range (*e)[n:] {
+				deprecate(entry, sch.Deprecated)
+			}
+		}
+	}
+}
+
+// crumbsToKey turns bread crumbs like `.rules.name` into a key schemaAt
+// is able to resolve, i.e. `rules.0.name`
+func crumbsToKey(breadCrumbs string) string {
+	return strings.Join(strings.Split(strings.TrimPrefix(breadCrumbs, "."), "."), ".0.")
+}
+
+func deprecate(entry exportEntry, message string) {
+	switch te := entry.(type) {
+	case *primitiveEntry:
+		te.Deprecated = message
+	case *mapEntry:
+		te.Deprecated = message
+	case *resourceEntry:
+		te.Deprecated = message
+	}
+}
+
+// writeDeprecation writes a comment line holding the deprecation message,
+// followed by the indent for the attribute itself
+func writeDeprecation(w io.Writer, indent string, message string) error {
+	if message == "" {
+		return nil
+	}
+	message = strings.ReplaceAll(message, "\n", " ")
+	_, err := w.Write([]byte(fmt.Sprintf("%s# deprecated: %s\n", strings.TrimSuffix(indent, "# "), message)))
+	return err
+}
+
+// DeprecatedMode determines how ExportOpt deals with attributes
+// the schema flags as deprecated
+type DeprecatedMode int
+
+const (
+	// DeprecatedAnnotate precedes deprecated attributes with a comment `# deprecated: <message>`
+	DeprecatedAnnotate DeprecatedMode = iota
+	// DeprecatedSkip leaves out deprecated attributes
+	DeprecatedSkip
+)
+
+// Order determines the order attributes and blocks are written in.
+// Regardless of the order, attributes always precede blocks.
+type Order int
+
+const (
+	// OrderDefault writes `name`, `description`, `type` and `enabled` first,
+	// followed by all other attributes in alphabetical order
+	OrderDefault Order = iota
+	// OrderAlphabetical writes attributes in alphabetical order
+	OrderAlphabetical
+	// OrderSchema writes attributes in the order they are declared in the schema,
+	// i.e. by their Index, which SchemaOf derives from the order of the struct fields.
+	// Attributes without Index follow in alphabetical order. Hand-written schemas,
+	// e.g. the ones returned by a Schemer, don't carry an Index unless they set it
+	// themselves, hence their attributes are written in alphabetical order.
+	OrderSchema
+	// OrderPriority writes the attributes listed in ExportOptions.Priority first,
+	// in the order they are listed, followed by all other attributes in alphabetical order
+	OrderPriority
+)
+
+// DefaultsMode determines how optional attributes holding their default value are written
+type DefaultsMode int
+
+const (
+	// DefaultsCommented writes them commented out, e.g. `# enabled = false`
+	DefaultsCommented DefaultsMode = iota
+	// DefaultsOmitted leaves them out
+	DefaultsOmitted
+	// DefaultsWritten writes them like any other attribute
+	DefaultsWritten
+)
+
+// ExportOptions customize the output of ExportOpt and ExportWith
+type ExportOptions struct {
+	// Deprecated determines how attributes flagged as deprecated are written.
+	// Defaults to DeprecatedAnnotate.
+	Deprecated DeprecatedMode
+	// Sensitive determines how values of attributes flagged as sensitive are written.
+	// Defaults to SensitiveVariable.
+	Sensitive SensitiveMode
+	// Placeholder replaces sensitive values if Sensitive is SensitivePlaceholder.
+	// If empty, DefaultPlaceholder is used.
+	Placeholder string
+	// Variables receives the declarations of the variables sensitive values have
+	// been replaced with, ready to be stored as `variables.tf`. If nil, sensitive
+	// values are replaced with the Placeholder instead.
+	Variables io.Writer
+	// Order determines the order attributes and blocks are written in.
+	// Defaults to OrderDefault.
+	Order Order
+	// Priority lists the attributes to write first if Order is OrderPriority
+	Priority []string
+	// Indent is the number of spaces per level of indentation. Defaults to 2.
+	Indent int
+	// Defaults determines how optional attributes holding their default value,
+	// e.g. `false` or an empty string, are written. Defaults to DefaultsCommented.
+	Defaults DefaultsMode
+	// HeredocThreshold is the minimum number of lines a string ending with a line
+	// break needs to have to be written as heredoc. Defaults to 2, i.e. any such
+	// string containing another line break. Negative values disable heredocs.
+	HeredocThreshold int
+	// TrimTrailingSpace omits the space otherwise following every attribute
+	TrimTrailingSpace bool
+}
+
+// exportLayout holds the ExportOptions relevant for writing entries,
+// with defaults filled in
+type exportLayout struct {
+	indent   string
+	order    Order
+	priority map[string]int
+	defaults DefaultsMode
+	heredoc  int
+	trim     bool
+}
+
+var defaultLayout = newExportLayout(nil)
+
+func newExportLayout(options *ExportOptions) *exportLayout {
+	if options == nil {
+		options = &ExportOptions{}
+	}
+	layout := &exportLayout{
+		indent:   "  ",
+		order:    options.Order,
+		priority: map[string]int{},
+		defaults: options.Defaults,
+		heredoc:  options.HeredocThreshold,
+		trim:     options.TrimTrailingSpace,
+	}
+	if options.Indent > 0 {
+		layout.indent = strings.Repeat(" ", options.Indent)
+	}
+	if layout.heredoc == 0 {
+		layout.heredoc = 2
+	}
+	for idx, key := range options.Priority {
+		if _, found := layout.priority[key]; !found {
+			layout.priority[key] = idx
+		}
+	}
+	return layout
+}
+
+// sort orders the entries of a body according to the configured Order.
+// The schema is the one of the body and only consulted for OrderSchema.
+func (l *exportLayout) sort(entries exportEntries, schema map[string]*Schema) {
+	if l.order == OrderDefault {
+		sort.SliceStable(entries, entries.Less)
+		return
 	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		_, iIsBlock := entries[i].(*resourceEntry)
+		_, jIsBlock := entries[j].(*resourceEntry)
+		if iIsBlock != jIsBlock {
+			return jIsBlock
+		}
+		iKey, jKey := entryKey(entries[i]), entryKey(entries[j])
+		if iRank, jRank := l.rank(iKey, schema), l.rank(jKey, schema); iRank != jRank {
+			return iRank < jRank
+		}
+		return iKey < jKey
+	})
+}
+
+// rank groups attributes which precede others regardless of their names
+func (l *exportLayout) rank(key string, schema map[string]*Schema) int {
+	switch l.order {
+	case OrderSchema:
+		if sch := schema[key]; sch != nil && sch.Index > 0 {
+			return sch.Index
+		}
+		return math.MaxInt
+	case OrderPriority:
+		if idx, found := l.priority[key]; found {
+			return idx
+		}
+		return len(l.priority)
+	}
+	return 0
+}
+
+func entryKey(entry exportEntry) string {
+	switch te := entry.(type) {
+	case *primitiveEntry:
+		return te.Key
+	case *mapEntry:
+		return te.Key
+	case *resourceEntry:
+		return te.Key
+	}
+	return ""
+}
+
+// writeEntries writes the given entries one per line. Optional entries holding
+// their default value are commented out, omitted or written as configured.
+func (l *exportLayout) writeEntries(w io.Writer, entries exportEntries, indent string, schema map[string]*Schema) error {
+	l.sort(entries, schema)
+	for _, entry := range entries {
+		entryIndent := indent
+		if entry.IsOptional() && entry.IsDefault() {
+			switch l.defaults {
+			case DefaultsOmitted:
+				continue
+			case DefaultsCommented:
+				entryIndent = indent + "# "
+			}
+		}
+		if err := entry.Write(w, entryIndent, l); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutDeprecated returns a copy of the given properties lacking all
+// attributes flagged as deprecated, including the ones within blocks
+func withoutDeprecated(m map[string]interface{}, schema map[string]*Schema) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range m {
+		sch := schema[k]
+		if sch == nil {
+			result[k] = v
+			continue
+		}
+		if sch.Deprecated != "" {
+			continue
+		}
+		resource, isBlock := sch.Elem.(*Resource)
+		entries, isList := v.([]interface{})
+		if !isBlock || !isList {
+			result[k] = v
+			continue
+		}
+		blocks := make([]interface{}, len(entries))
+		for idx, entry := range entries {
+			if block, ok := entry.(map[string]interface{}); ok {
+				blocks[idx] = withoutDeprecated(block, resource.Schema)
+			} else {
+				blocks[idx] = entry
+			}
+		}
+		result[k] = blocks
+	}
+	return result
 }
 
 type Schemer interface {
 	Schema() map[string]*Schema
 }
 
-func ExportOpt(marshaler Marshaler, w io.Writer) error {
+// ExportOpt writes the HCL representation of the given Marshaler, taking its schema
+// into account if it implements Schemer. Optional attributes holding their default value
+// are commented out. Deprecated attributes are annotated with a comment, unless the
+// ExportOptions specify to skip them.
+// Values of sensitive attributes are replaced with references to variables (or a
+// placeholder), unless the ExportOptions specify to write them verbatim.
+func ExportOpt(marshaler Marshaler, w io.Writer, opts ...*ExportOptions) error {
+	var options *ExportOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	return ExportWith(marshaler, w, options)
+}
+
+// ExportWith writes the HCL representation of the given Marshaler like ExportOpt does,
+// with the layout (order, indentation, defaults, heredocs and trailing whitespace)
+// controlled by the given ExportOptions. Options being nil are equivalent to
+// the zero value of ExportOptions.
+func ExportWith(marshaler Marshaler, w io.Writer, options *ExportOptions) error {
 	var m map[string]interface{}
 	var err error
 	if m, err = marshaler.MarshalHCL(); err != nil {
@@ -170,11 +441,29 @@ func ExportOpt(marshaler Marshaler, w io.Writer) error {
 	if schemer, ok := marshaler.(Schemer); ok {
 		schema = schemer.Schema()
 	}
+	if options == nil {
+		options = &ExportOptions{}
+	}
+	if options.Deprecated == DeprecatedSkip {
+		m = withoutDeprecated(m, schema)
+	}
+	var variables []sensitiveVariable
+	if options.Sensitive != SensitiveVerbatim {
+		r := &redactor{options: options, names: map[string]bool{}}
+		m = r.redact("", m, schema, false)
+		variables = r.variables
+	}
 	// if schema != nil {
 	// 	data, _ := json.MarshalIndent(schema, "", "  ")
 	// 	fmt.Println(string(data))
 	// }
-	return export(m, w, schema)
+	if err := exportWith(m, w, schema, newExportLayout(options)); err != nil {
+		return err
+	}
+	if options.Variables != nil {
+		return writeVariables(options.Variables, variables)
+	}
+	return nil
 }
 
 func Export(marshaler Marshaler, w io.Writer) error {
@@ -197,28 +486,13 @@ func ExtExport(marshaler ExtMarshaler, w io.Writer) error {
 }
 
 func export(m map[string]interface{}, w io.Writer, schema map[string]*Schema) error {
-	var err error
+	return exportWith(m, w, schema, defaultLayout)
+}
+
+func exportWith(m map[string]interface{}, w io.Writer, schema map[string]*Schema, layout *exportLayout) error {
 	ents := exportEntries{}
 	ents.handle(m, "", schema)
-	sort.SliceStable(ents, ents.Less)
-	for _, entry := range ents {
-		if !(entry.IsOptional() && entry.IsDefault()) {
-			if err := entry.Write(w, "  "); err != nil {
-				return err
-			}
-			if _, err := w.Write([]byte("\n")); err != nil {
-				return err
-			}
-		} else {
-			if err := entry.Write(w, "  # "); err != nil {
-				return err
-			}
-			if _, err := w.Write([]byte("\n")); err != nil {
-				return err
-			}
-		}
-	}
-	return err
+	return layout.writeEntries(w, ents, layout.indent, schema)
 }
 
 type primitiveEntry struct {
@@ -227,27 +501,22 @@ type primitiveEntry struct {
 	Optional    bool
 	BreadCrumbs string
 	Value       interface{}
+	Deprecated  string
 }
 
-func jsonenc(v interface{}, indent string) string {
-	switch rv := v.(type) {
-	case string:
-		if strings.Contains(rv, "\n") {
-			return "<<-EOT\n" + indent + "  " + strings.ReplaceAll(rv, "\n", "\n"+indent+"  ") + "\n" + indent + "EOT"
-		}
-	case *string:
-		erv := *rv
-		if strings.Contains(erv, "\n") {
-			return "<<-EOT\n" + indent + "  " + strings.ReplaceAll(erv, "\n", "\n"+indent+"  ") + "\n" + indent + "EOT"
-		}
-	default:
+func (pe *primitiveEntry) Write(w io.Writer, indent string, layout *exportLayout) error {
+	if err := writeDeprecation(w, indent, pe.Deprecated); err != nil {
+		return err
 	}
-	bytes, _ := json.Marshal(v)
-	return string(bytes)
-}
-
-func (pe *primitiveEntry) Write(w io.Writer, indent string) error {
-	_, err := w.Write([]byte(fmt.Sprintf("%s%v = %v ", indent, pe.Key, jsonenc(pe.Value, indent))))
+	value, err := layout.encode(pe.Value, indent)
+	if err != nil {
+		return encodeError(pe.Key, err)
+	}
+	line := fmt.Sprintf("%s%v = %v ", indent, pe.Key, value)
+	if layout.trim {
+		line = strings.TrimSuffix(line, " ")
+	}
+	_, err = w.Write([]byte(line))
 	return err
 }
 
@@ -270,18 +539,115 @@ func (pe *primitiveEntry) IsLessThan(other exportEntry) bool {
 	switch ro := other.(type) {
 	case *primitiveEntry:
 		return strings.Compare(sk(pe.Key), sk(ro.Key)) < 0
+	case *mapEntry:
+		return strings.Compare(sk(pe.Key), sk(ro.Key)) < 0
+	case *resourceEntry:
+		return true
+	}
+	return false
+}
+
+// mapEntry represents attributes of type map, which are getting written
+// as object expressions of the form `key = { ... }`
+type mapEntry struct {
+	Key        string
+	Optional   bool
+	Value      map[string]interface{}
+	Deprecated string
+}
+
+func (me *mapEntry) Write(w io.Writer, indent string, layout *exportLayout) error {
+	if err := writeDeprecation(w, indent, me.Deprecated); err != nil {
+		return err
+	}
+	value, err := layout.object(me.Value, indent)
+	if err != nil {
+		return encodeError(me.Key, err)
+	}
+	_, err = w.Write([]byte(fmt.Sprintf("%s%v = %v", indent, me.Key, value)))
+	return err
+}
+
+func (me *mapEntry) IsOptional() bool {
+	return me.Optional
+}
+
+func (me *mapEntry) IsDefault() bool {
+	return len(me.Value) == 0
+}
+
+func (me *mapEntry) IsLessThan(other exportEntry) bool {
+	switch ro := other.(type) {
+	case *primitiveEntry:
+		return strings.Compare(sk(me.Key), sk(ro.Key)) < 0
+	case *mapEntry:
+		return strings.Compare(sk(me.Key), sk(ro.Key)) < 0
 	case *resourceEntry:
 		return true
 	}
 	return false
 }
 
+// object writes maps as object expressions, one attribute per line
+func (l *exportLayout) object(m map[string]interface{}, indent string) (string, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	sb := new(strings.Builder)
+	sb.WriteString("{\n")
+	for _, k := range keys {
+		value, err := l.value(m[k], indent+l.indent)
+		if err != nil {
+			return "", encodeError(k, err)
+		}
+		sb.WriteString(fmt.Sprintf("%s%s%s = %s\n", indent, l.indent, hclKey(k), value))
+	}
+	sb.WriteString(indent + "}")
+	return sb.String(), nil
+}
+
+func (l *exportLayout) value(v interface{}, indent string) (string, error) {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		return l.object(tv, indent)
+	case []interface{}:
+		elems := []string{}
+		for _, elem := range tv {
+			value, err := l.value(elem, indent)
+			if err != nil {
+				return "", err
+			}
+			elems = append(elems, value)
+		}
+		return "[" + strings.Join(elems, listSeparator) + "]", nil
+	default:
+		return l.encode(v, indent)
+	}
+}
+
+// hclKey quotes keys of object expressions which aren't valid identifiers
+func hclKey(k string) string {
+	if k == "" {
+		return hclString(k)
+	}
+	for idx, r := range k {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (idx > 0 && (r == '-' || (r >= '0' && r <= '9')))) {
+			return hclString(k)
+		}
+	}
+	return k
+}
+
 type resourceEntry struct {
 	Indent      string
 	Key         string
 	BreadCrumbs string
 	Optional    bool
 	Entries     exportEntries
+	Deprecated  string
+	Schema      map[string]*Schema
 }
 
 func (pe *resourceEntry) IsOptional() bool {
@@ -301,28 +667,16 @@ func (re *resourceEntry) IsLessThan(other exportEntry) bool {
 	}
 	return false
 }
-func (re *resourceEntry) Write(w io.Writer, indent string) error {
+func (re *resourceEntry) Write(w io.Writer, indent string, layout *exportLayout) error {
+	if err := writeDeprecation(w, indent, re.Deprecated); err != nil {
+		return err
+	}
 	s := fmt.Sprintf("%s%v {\n", indent, re.Key)
 	if _, err := w.Write([]byte(s)); err != nil {
 		return err
 	}
-	sort.SliceStable(re.Entries, re.Entries.Less)
-	for _, entry := range re.Entries {
-		if !(entry.IsOptional() && entry.IsDefault()) {
-			if err := entry.Write(w, indent+"  "); err != nil {
-				return err
-			}
-			if _, err := w.Write([]byte("\n")); err != nil {
-				return err
-			}
-		} else {
-			if err := entry.Write(w, indent+"  # "); err != nil {
-				return err
-			}
-			if _, err := w.Write([]byte("\n")); err != nil {
-				return err
-			}
-		}
+	if err := layout.writeEntries(w, re.Entries, indent+layout.indent, re.Schema); err != nil {
+		return err
 	}
 	if _, err := w.Write([]byte(indent + "}")); err != nil {
 		return err
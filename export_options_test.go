@@ -0,0 +1,107 @@
+package hcl_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type Layout struct {
+	Name    string   `hcl:"name"`
+	Enabled bool     `hcl:"enabled,optional"`
+	Zone    string   `hcl:"zone"`
+	ID      string   `hcl:"id,computed,omitempty"`
+	Script  string   `hcl:"script,omitempty"`
+	Rules   []*Ratio `hcl:"rules,omitempty"`
+}
+
+type Ratio struct {
+	Label string  `hcl:"label,optional"`
+	Value float64 `hcl:"value"`
+}
+
+func TestExportWith(t *testing.T) {
+	layout := hcl.AsMarshaler(&Layout{
+		Name:   "layout",
+		Zone:   "eu",
+		Script: "echo a\necho b\n",
+		ID:     "id",
+		Rules:  []*Ratio{{Value: 0.5}},
+	})
+
+	tests := []struct {
+		name     string
+		options  *hcl.ExportOptions
+		expected string
+	}{
+		{"defaults", nil, `  name = "layout" 
+  # enabled = false 
+  id = "id" 
+  script = <<-EOT
+    echo a
+    echo b
+  EOT 
+  zone = "eu" 
+  rules {
+    # label = "" 
+    value = 0.5 
+  }
+`},
+		{"alphabetical, omitted defaults, indent 4", &hcl.ExportOptions{Order: hcl.OrderAlphabetical, Defaults: hcl.DefaultsOmitted, Indent: 4}, `    id = "id" 
+    name = "layout" 
+    script = <<-EOT
+        echo a
+        echo b
+    EOT 
+    zone = "eu" 
+    rules {
+        value = 0.5 
+    }
+`},
+		{"schema order, written defaults, trimmed", &hcl.ExportOptions{Order: hcl.OrderSchema, Defaults: hcl.DefaultsWritten, TrimTrailingSpace: true}, `  name = "layout"
+  enabled = false
+  zone = "eu"
+  id = "id"
+  script = <<-EOT
+    echo a
+    echo b
+  EOT
+  rules {
+    label = ""
+    value = 0.5
+  }
+`},
+		{"priority order, heredoc threshold", &hcl.ExportOptions{Order: hcl.OrderPriority, Priority: []string{"zone", "script"}, HeredocThreshold: 3, Defaults: hcl.DefaultsOmitted}, `  zone = "eu" 
+  script = "echo a\necho b\n" 
+  id = "id" 
+  name = "layout" 
+  rules {
+    value = 0.5 
+  }
+`},
+		{"heredocs disabled", &hcl.ExportOptions{HeredocThreshold: -1, Defaults: hcl.DefaultsOmitted}, `  name = "layout" 
+  id = "id" 
+  script = "echo a\necho b\n" 
+  zone = "eu" 
+  rules {
+    value = 0.5 
+  }
+`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := hcl.ExportWith(layout, buf, test.options); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != test.expected {
+				t.Errorf("expected: %v, actual: %v", test.expected, buf.String())
+			}
+			if _, err := hcl.Import(strings.NewReader(buf.String()), nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package hcl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ResourceOptions specify the meta-arguments of the block written by ExportResource.
+// Count, ForEach, Provider, DependsOn and IgnoreChanges are expressions and
+// therefore written as they are, e.g. `var.enabled ? 1 : 0` or `aws.west`.
+// ExportResource rejects them with an EncodeError unless they pass Expr.Validate.
+type ResourceOptions struct {
+	// Data produces a `data` block instead of a `resource` block
+	Data          bool
+	Count         string
+	ForEach       string
+	Provider      string
+	DependsOn     []string
+	IgnoreChanges []string
+	// Export customizes how the attributes of the resource are written
+	Export *ExportOptions
+}
+
+// ExportResource writes a complete `resource` (or `data`) block of the given type and name,
+// holding the attributes produced by ExportOpt. The meta-arguments `count`, `for_each` and
+// `provider` precede the attributes, `depends_on` and `lifecycle` follow them.
+// Nothing is written to `w` unless exporting the attributes succeeds.
+func ExportResource(w io.Writer, resourceType string, name string, marshaler Marshaler, opts ...*ResourceOptions) error {
+	options := &ResourceOptions{}
+	if len(opts) > 0 && opts[0] != nil {
+		options = opts[0]
+	}
+	if !isIdentifier(resourceType) {
+		return fmt.Errorf("invalid resource type `%s`", resourceType)
+	}
+	if !isIdentifier(name) {
+		return fmt.Errorf("invalid resource name `%s`", name)
+	}
+	if options.Count != "" && options.ForEach != "" {
+		return errors.New("`count` and `for_each` are mutually exclusive")
+	}
+	if err := options.validate(); err != nil {
+		return err
+	}
+	keyword := "resource"
+	if options.Data {
+		if len(options.IgnoreChanges) > 0 {
+			return errors.New("`ignore_changes` is not supported for data sources")
+		}
+		keyword = "data"
+	}
+
+	body := new(bytes.Buffer)
+	if err := ExportOpt(marshaler, body, options.Export); err != nil {
+		return err
+	}
+
+	indent := newExportLayout(options.Export).indent
+	block := new(strings.Builder)
+	block.WriteString(fmt.Sprintf("%s %q %q {\n", keyword, resourceType, name))
+	meta := false
+	for _, arg := range []struct{ name, value string }{
+		{"count", options.Count},
+		{"for_each", options.ForEach},
+		{"provider", options.Provider},
+	} {
+		if arg.value != "" {
+			block.WriteString(fmt.Sprintf("%s%s = %s\n", indent, arg.name, arg.value))
+			meta = true
+		}
+	}
+	if meta {
+		block.WriteString("\n")
+	}
+	block.Write(body.Bytes())
+	if len(options.DependsOn) > 0 {
+		block.WriteString(fmt.Sprintf("\n%sdepends_on = [%s]\n", indent, strings.Join(options.DependsOn, ", ")))
+	}
+	if len(options.IgnoreChanges) > 0 {
+		block.WriteString(fmt.Sprintf("\n%slifecycle {\n%s%signore_changes = [%s]\n%s}\n", indent, indent, indent, strings.Join(options.IgnoreChanges, ", "), indent))
+	}
+	block.WriteString("}\n")
+	_, err := w.Write([]byte(block.String()))
+	return err
+}
+
+// validate checks whether the meta-arguments are plausible expressions
+func (options *ResourceOptions) validate() error {
+	for _, arg := range []struct {
+		name   string
+		values []string
+		list   bool
+	}{
+		{"count", []string{options.Count}, false},
+		{"for_each", []string{options.ForEach}, false},
+		{"provider", []string{options.Provider}, false},
+		{"depends_on", options.DependsOn, true},
+		{"lifecycle.ignore_changes", options.IgnoreChanges, true},
+	} {
+		for idx, value := range arg.values {
+			if value == "" && !arg.list {
+				continue
+			}
+			address := arg.name
+			if arg.list {
+				address = fmt.Sprintf("%s.%d", arg.name, idx)
+			}
+			if err := Expr(value).Validate(); err != nil {
+				return encodeError(address, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isIdentifier reports whether the given string is a valid HCL identifier
+func isIdentifier(s string) bool {
+	return s != "" && hclKey(s) == s
+}
@@ -0,0 +1,81 @@
+package hcl_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+func TestExportResource(t *testing.T) {
+	threshold := hcl.AsMarshaler(&Threshold{Value: 1.5, Unit: "ms"})
+
+	buf := new(bytes.Buffer)
+	if err := hcl.ExportResource(buf, "example_threshold", "latency", threshold, &hcl.ResourceOptions{
+		Count:         "var.enabled ? 1 : 0",
+		Provider:      "example.west",
+		DependsOn:     []string{"example_alert.main", "example_alert.backup"},
+		IgnoreChanges: []string{"unit"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `resource "example_threshold" "latency" {
+  count = var.enabled ? 1 : 0
+  provider = example.west
+
+  unit = "ms" 
+  value = 1.5 
+
+  depends_on = [example_alert.main, example_alert.backup]
+
+  lifecycle {
+    ignore_changes = [unit]
+  }
+}
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+
+	buf = new(bytes.Buffer)
+	if err := hcl.ExportResource(buf, "example_threshold", "latency", threshold, &hcl.ResourceOptions{Data: true, ForEach: "toset(var.units)"}); err != nil {
+		t.Fatal(err)
+	}
+	expected = `data "example_threshold" "latency" {
+  for_each = toset(var.units)
+
+  unit = "ms" 
+  value = 1.5 
+}
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+
+	for _, opts := range []*hcl.ResourceOptions{
+		{Count: "1", ForEach: "var.units"},
+		{Data: true, IgnoreChanges: []string{"unit"}},
+		{Count: "var.enabled ?"},
+		{ForEach: "toset(var.units"},
+		{Provider: "aws."},
+		{DependsOn: []string{"example_alert.main", ""}},
+		{IgnoreChanges: []string{"unit\nthreshold"}},
+	} {
+		if err := hcl.ExportResource(new(bytes.Buffer), "example_threshold", "latency", threshold, opts); err == nil {
+			t.Errorf("expected: error, actual: %v", err)
+		}
+	}
+	if err := hcl.ExportResource(new(bytes.Buffer), "example threshold", "latency", threshold); err == nil {
+		t.Errorf("expected: error, actual: %v", err)
+	}
+
+	// failing to export the attributes leaves the writer untouched
+	buf = new(bytes.Buffer)
+	if err := hcl.ExportResource(buf, "example_condition", "failing", &failingCondition{Fail: true}, &hcl.ResourceOptions{Count: "1"}); !errors.Is(err, errInvalid) {
+		t.Errorf("expected: %v, actual: %v", errInvalid, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected: %v, actual: %v", "", buf.String())
+	}
+}
@@ -0,0 +1,120 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expr is a raw HCL expression, e.g. `var.region`, `data.example_zone.main.id`
+// or `jsonencode(local.settings)`. Properties.Encode accepts it like any other value
+// and Export writes it unquoted, which allows to refer to variables and other resources
+// instead of hardcoding their values. Import restores expressions it doesn't
+// understand as Expr. Decoding them into an Expr keeps them as they are, decoding
+// them into a string yields the text of the expression.
+type Expr string
+
+// Ref produces an expression traversing the given names, e.g.
+// `Ref("example_alert", "main", "id")` yields `example_alert.main.id`.
+// Numeric names are written as index, i.e. `Ref("var", "hosts", "0")` yields `var.hosts[0]`.
+func Ref(names ...string) Expr {
+	sb := new(strings.Builder)
+	for idx, name := range names {
+		if _, err := strconv.Atoi(name); err == nil && idx > 0 {
+			sb.WriteString("[" + name + "]")
+			continue
+		}
+		if idx > 0 {
+			sb.WriteString(".")
+		}
+		sb.WriteString(name)
+	}
+	return Expr(sb.String())
+}
+
+// Validate checks whether the expression is syntactically plausible, i.e. it is not empty,
+// brackets and string literals (including template sequences within them) are balanced,
+// line breaks only occur within brackets and it neither starts nor ends with an operator.
+// It doesn't verify whether the expression would actually evaluate.
+func (e Expr) Validate() error {
+	s := strings.TrimSpace(string(e))
+	if s == "" {
+		return errors.New("expression is empty")
+	}
+	if strings.ContainsRune("=)]},.*/%&|?:>", rune(s[0])) {
+		return fmt.Errorf("invalid expression `%s`: unexpected %q at the beginning", s, s[0])
+	}
+	if strings.ContainsRune("=([{,.+-/%&|!?:<>", rune(s[len(s)-1])) {
+		return fmt.Errorf("invalid expression `%s`: unexpected %q at the end", s, s[len(s)-1])
+	}
+	if _, err := scanExpression(s, false); err != nil {
+		return fmt.Errorf("invalid expression `%s`: %s", s, err.Error())
+	}
+	return nil
+}
+
+// scanExpression returns the length of the expression at the beginning of `s`.
+// If `embedded` is true, the expression ends at the first line break, comment,
+// comma or closing bracket outside of brackets and string literals. Otherwise
+// the whole of `s` is expected to be a single expression.
+func scanExpression(s string, embedded bool) (int, error) {
+	// closers holds the expected closing characters; a `"` marks a string literal
+	closers := []byte{}
+	inString := func() bool { return len(closers) > 0 && closers[len(closers)-1] == '"' }
+	for pos := 0; pos < len(s); pos++ {
+		c := s[pos]
+		if inString() {
+			switch {
+			case c == '\\':
+				pos++
+			case c == '\n':
+				return 0, errors.New("unterminated string literal")
+			case c == '"':
+				closers = closers[:len(closers)-1]
+			case (c == '$' || c == '%') && strings.HasPrefix(s[pos+1:], "{"):
+				if pos > 0 && s[pos-1] == c {
+					// `$${` and `%%{` are escaped template sequences
+					continue
+				}
+				closers = append(closers, '}')
+				pos++
+			}
+			continue
+		}
+		if len(closers) == 0 && embedded {
+			switch {
+			case c == '\n' || c == ',' || c == ']' || c == '}' || c == ')' || c == '#':
+				return pos, nil
+			case strings.HasPrefix(s[pos:], "//"):
+				return pos, nil
+			}
+		}
+		switch c {
+		case '"':
+			closers = append(closers, '"')
+		case '(':
+			closers = append(closers, ')')
+		case '[':
+			closers = append(closers, ']')
+		case '{':
+			closers = append(closers, '}')
+		case ')', ']', '}':
+			if len(closers) == 0 || closers[len(closers)-1] != c {
+				return 0, fmt.Errorf("unexpected %q", c)
+			}
+			closers = closers[:len(closers)-1]
+		case '\n':
+			if len(closers) == 0 {
+				return 0, errors.New("unexpected line break")
+			}
+		}
+	}
+	if len(closers) > 0 {
+		if inString() {
+			return 0, errors.New("unterminated string literal")
+		}
+		return 0, fmt.Errorf("expected %q", closers[len(closers)-1])
+	}
+	return len(s), nil
+}
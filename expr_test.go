@@ -0,0 +1,149 @@
+package hcl_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+func TestExprValidate(t *testing.T) {
+	for _, valid := range []hcl.Expr{
+		"var.region",
+		"data.example_zone.main.id",
+		`jsonencode({ a = "b" })`,
+		"var.hosts[0]",
+		"aws_instance.web[*].id",
+		`"${var.prefix}-name"`,
+		`"$${literal"`,
+		"var.enabled ? 1 : 0",
+		"[\n  var.a,\n  var.b,\n]",
+		hcl.Ref("example_alert", "main", "id"),
+	} {
+		if err := valid.Validate(); err != nil {
+			t.Errorf("%s: expected: %v, actual: %v", valid, nil, err)
+		}
+	}
+	for _, invalid := range []hcl.Expr{
+		"",
+		"  ",
+		"var.region.",
+		"jsonencode({ a = 1 }",
+		"var.hosts[0)",
+		`"unterminated`,
+		`"${var.prefix"`,
+		"= var.x",
+		"var.a +",
+		"var.a\nvar.b",
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("%q: expected: error, actual: %v", invalid, err)
+		}
+	}
+	if expected, actual := hcl.Expr("var.hosts[0].name"), hcl.Ref("var", "hosts", "0", "name"); actual != expected {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+type rawProperties hcl.Properties
+
+func (me rawProperties) MarshalHCL() (map[string]interface{}, error) {
+	return me, nil
+}
+
+func TestExprExport(t *testing.T) {
+	properties := hcl.Properties{}
+	if err := properties.Encode("region", hcl.Expr("var.region")); err != nil {
+		t.Fatal(err)
+	}
+	if err := properties.Encode("zone_id", hcl.Ref("data", "example_zone", "main", "id")); err != nil {
+		t.Fatal(err)
+	}
+	if err := properties.Encode("name", "name"); err != nil {
+		t.Fatal(err)
+	}
+	if err := properties.Encode("hosts", []hcl.Expr{"var.primary", `"backup"`}); err != nil {
+		t.Fatal(err)
+	}
+	if err := properties.Encode("broken", hcl.Expr("var.")); err == nil {
+		t.Errorf("expected: error, actual: %v", err)
+	}
+	if err := properties.Encode("broken", []hcl.Expr{"var.a", "var."}); err == nil {
+		t.Errorf("expected: error, actual: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := hcl.Export(rawProperties(properties), buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `  name = "name" 
+  hosts = [var.primary,"backup"] 
+  region = var.region 
+  zone_id = data.example_zone.main.id 
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+
+	imported, err := hcl.Import(strings.NewReader(buf.String()+"  settings = jsonencode({ a = [1, 2] }) # comment\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedProperties := hcl.Properties{
+		"name":     "name",
+		"hosts":    []interface{}{hcl.Expr("var.primary"), "backup"},
+		"region":   hcl.Expr("var.region"),
+		"zone_id":  hcl.Expr("data.example_zone.main.id"),
+		"settings": hcl.Expr("jsonencode({ a = [1, 2] })"),
+	}
+	if !reflect.DeepEqual(imported, expectedProperties) {
+		t.Errorf("expected: %v, actual: %v", expectedProperties, imported)
+	}
+}
+
+func TestExprDecode(t *testing.T) {
+	decoder, err := hcl.ImportDecoder(strings.NewReader("  region = var.region\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var region string
+	if err := decoder.Decode("region", &region); err != nil {
+		t.Fatal(err)
+	}
+	if region != "var.region" {
+		t.Errorf("expected: %v, actual: %v", "var.region", region)
+	}
+	var expr hcl.Expr
+	if err := decoder.Decode("region", &expr); err != nil {
+		t.Fatal(err)
+	}
+	if expr != hcl.Expr("var.region") {
+		t.Errorf("expected: %v, actual: %v", hcl.Expr("var.region"), expr)
+	}
+}
+
+func TestExprImportTemplates(t *testing.T) {
+	imported, err := hcl.Import(strings.NewReader(`  name = "${var.prefix}-name"
+  literal = "$${var.prefix}-name"
+  offset = -var.offset
+  negative = -1.5
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := hcl.Properties{
+		"name":     hcl.Expr(`"${var.prefix}-name"`),
+		"literal":  "${var.prefix}-name",
+		"offset":   hcl.Expr("-var.offset"),
+		"negative": -1.5,
+	}
+	if !reflect.DeepEqual(imported, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, imported)
+	}
+
+	if _, err := hcl.Import(strings.NewReader("  script = <<EOT\necho ${var.name}\nEOT\n"), nil); err == nil {
+		t.Errorf("expected: error, actual: %v", err)
+	}
+}
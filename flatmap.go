@@ -0,0 +1,355 @@
+package hcl
+
+import (
+	"fmt"
+	"hash/crc32"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flatten converts Properties, nested the way Properties.Encode produces them,
+// into the flat key/value representation used for addressing attributes
+// within a Decoder, e.g. `rules.#`, `rules.0.name` or `labels.%`.
+// Entries of attributes of TypeSet are addressed via their hash instead of their index.
+// The schema is optional and only consulted for distinguishing sets from lists.
+func Flatten(properties Properties, schema map[string]*Schema) map[string]string {
+	result := map[string]string{}
+	flattenObject("", map[string]interface{}(properties), schema, result)
+	return result
+}
+
+func flattenObject(prefix string, m map[string]interface{}, schema map[string]*Schema, result map[string]string) {
+	for k, v := range m {
+		flattenValue(joinAddress(prefix, k), v, schema[k], result)
+	}
+}
+
+func flattenValue(key string, value interface{}, sch *Schema, result map[string]string) {
+	if value == nil {
+		return
+	}
+	if m, ok := asMap(value); ok {
+		if sch != nil && sch.Type != TypeMap {
+			flattenObject(key, m, elemSchema(sch), result)
+			return
+		}
+		result[key+".%"] = strconv.Itoa(len(m))
+		var valueSchema *Schema
+		if sch != nil {
+			valueSchema, _ = sch.Elem.(*Schema)
+		}
+		for k, v := range m {
+			if nested, ok := asMap(v); ok {
+				flattenObject(key+"."+k, nested, nil, result)
+			} else {
+				flattenValue(key+"."+k, v, valueSchema, result)
+			}
+		}
+		return
+	}
+	if entries, ok := listOf(value); ok {
+		result[key+".#"] = strconv.Itoa(len(entries))
+		var elem *Schema
+		if sch != nil {
+			elem, _ = sch.Elem.(*Schema)
+		}
+		for idx, entry := range entries {
+			entryKey := fmt.Sprintf("%v.%d", key, idx)
+			if sch != nil && sch.Type == TypeSet {
+				entryKey = fmt.Sprintf("%v.%d", key, hashValue(entry))
+			}
+			if m, ok := asMap(entry); ok {
+				flattenObject(entryKey, m, elemSchema(sch), result)
+			} else {
+				flattenValue(entryKey, entry, elem, result)
+			}
+		}
+		return
+	}
+	result[key] = formatPrimitive(value)
+}
+
+// elemSchema returns the schema of the blocks of the given attribute
+func elemSchema(sch *Schema) map[string]*Schema {
+	if sch == nil {
+		return nil
+	}
+	if resource, ok := sch.Elem.(*Resource); ok {
+		return resource.Schema
+	}
+	return nil
+}
+
+func formatPrimitive(v interface{}) string {
+	switch tv := v.(type) {
+	case string:
+		return tv
+	case float64:
+		return strconv.FormatFloat(tv, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(tv), 'f', -1, 32)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// hashValue calculates the hash entries of sets are getting addressed with.
+// Like Terraform's `schema.HashString` it is based on CRC32 and never negative.
+func hashValue(v interface{}) int {
+	sb := new(strings.Builder)
+	writeCanonical(sb, v)
+	hash := int(crc32.ChecksumIEEE([]byte(sb.String())))
+	if hash < 0 {
+		return -hash
+	}
+	return hash
+}
+
+func writeCanonical(sb *strings.Builder, v interface{}) {
+	if m, ok := asMap(v); ok {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sb.WriteString(k)
+			sb.WriteString("=")
+			writeCanonical(sb, m[k])
+			sb.WriteString(";")
+		}
+		return
+	}
+	if entries, ok := listOf(v); ok {
+		for _, entry := range entries {
+			writeCanonical(sb, entry)
+			sb.WriteString(";")
+		}
+		return
+	}
+	if v != nil {
+		sb.WriteString(formatPrimitive(v))
+	}
+}
+
+// Unflatten converts flat key/value pairs as produced by Flatten back into Properties.
+// The schema determines the types of primitive values and whether nested keys
+// represent lists, sets, maps or blocks. Keys not covered by the schema are
+// restored as strings, lists of strings or maps of strings.
+func Unflatten(flat map[string]string, schema map[string]*Schema) (Properties, error) {
+	m, err := unflattenObject(flat, "", schema)
+	if err != nil {
+		return nil, err
+	}
+	return Properties(m), nil
+}
+
+func unflattenObject(flat map[string]string, prefix string, schema map[string]*Schema) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	for _, name := range childNames(flat, prefix) {
+		key := joinAddress(prefix, name)
+		value, found, err := unflattenValue(flat, key, schema[name])
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result[name] = value
+		}
+	}
+	return result, nil
+}
+
+func unflattenValue(flat map[string]string, key string, sch *Schema) (interface{}, bool, error) {
+	if sch == nil {
+		value, found := inferValue(flat, key)
+		return value, found, nil
+	}
+	switch sch.Type {
+	case TypeList, TypeSet:
+		if _, found := flat[key+".#"]; !found {
+			return nil, false, nil
+		}
+		indices := childNames(flat, key)
+		sort.SliceStable(indices, func(i, j int) bool {
+			a, aErr := strconv.Atoi(indices[i])
+			b, bErr := strconv.Atoi(indices[j])
+			if aErr != nil || bErr != nil {
+				return indices[i] < indices[j]
+			}
+			return a < b
+		})
+		entries := []interface{}{}
+		for _, idx := range indices {
+			if idx == "#" {
+				continue
+			}
+			var entry interface{}
+			var err error
+			switch elem := sch.Elem.(type) {
+			case *Resource:
+				entry, err = unflattenObject(flat, key+"."+idx, elem.Schema)
+			case *Schema:
+				entry, _, err = unflattenValue(flat, key+"."+idx, elem)
+			default:
+				entry, _ = inferValue(flat, key+"."+idx)
+			}
+			if err != nil {
+				return nil, false, err
+			}
+			entries = append(entries, entry)
+		}
+		return typedList(entries), true, nil
+	case TypeMap:
+		if _, found := flat[key+".%"]; !found {
+			return nil, false, nil
+		}
+		elem, _ := sch.Elem.(*Schema)
+		result := map[string]interface{}{}
+		for _, name := range mapKeys(flat, key, elem) {
+			value, _, err := unflattenValue(flat, key+"."+name, elem)
+			if err != nil {
+				return nil, false, err
+			}
+			result[name] = value
+		}
+		return result, true, nil
+	default:
+		value, found := flat[key]
+		if !found {
+			return nil, false, nil
+		}
+		parsed, err := parsePrimitive(value, sch.Type)
+		if err != nil {
+			return nil, false, decodeError(key, err)
+		}
+		return parsed, true, nil
+	}
+}
+
+func parsePrimitive(value string, valueType ValueType) (interface{}, error) {
+	switch valueType {
+	case TypeBool:
+		return strconv.ParseBool(value)
+	case TypeInt:
+		return strconv.Atoi(value)
+	case TypeFloat:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return value, nil
+	}
+}
+
+// inferValue restores values not covered by a schema
+func inferValue(flat map[string]string, key string) (interface{}, bool) {
+	if value, found := flat[key]; found {
+		return value, true
+	}
+	if _, found := flat[key+".#"]; found {
+		value, _, _ := unflattenValue(flat, key, &Schema{Type: TypeList})
+		return value, true
+	}
+	if _, found := flat[key+".%"]; found {
+		value, _, _ := unflattenValue(flat, key, &Schema{Type: TypeMap})
+		return value, true
+	}
+	if names := childNames(flat, key); len(names) > 0 {
+		value, _ := unflattenObject(flat, key, nil)
+		return value, true
+	}
+	return nil, false
+}
+
+// typedList stores lists of strings and floats the way Properties.Encode does
+func typedList(entries []interface{}) interface{} {
+	if len(entries) == 0 {
+		return entries
+	}
+	switch entries[0].(type) {
+	case string:
+		return typedSlice(entries, reflect.TypeOf([]string{}))
+	case float64:
+		return typedSlice(entries, reflect.TypeOf([]float64{}))
+	}
+	return entries
+}
+
+func typedSlice(entries []interface{}, t reflect.Type) interface{} {
+	vSlice := reflect.MakeSlice(t, 0, len(entries))
+	for _, entry := range entries {
+		vEntry := reflect.ValueOf(entry)
+		if vEntry.Type() != t.Elem() {
+			return entries
+		}
+		vSlice = reflect.Append(vSlice, vEntry)
+	}
+	return vSlice.Interface()
+}
+
+// mapKeys returns the keys of the map stored under the given prefix.
+// Map keys may contain dots, hence entries holding lists or maps are recognized
+// by their `.#` or `.%` marker and any other key below the prefix is a primitive entry.
+func mapKeys(flat map[string]string, prefix string, elem *Schema) []string {
+	compound := elem == nil || elem.Type == TypeList || elem.Type == TypeSet || elem.Type == TypeMap
+	candidates := []string{}
+	for k := range flat {
+		if !strings.HasPrefix(k, prefix+".") || k == prefix+".%" {
+			continue
+		}
+		rest := k[len(prefix)+1:]
+		if compound && (strings.HasSuffix(rest, ".#") || strings.HasSuffix(rest, ".%")) {
+			candidates = append(candidates, rest[:len(rest)-2])
+		}
+	}
+	// the shortest marked key is the map key, longer ones belong to nested values
+	sort.Slice(candidates, func(i, j int) bool { return len(candidates[i]) < len(candidates[j]) })
+	nested := []string{}
+	within := func(rest string) bool {
+		for _, name := range nested {
+			if rest == name || strings.HasPrefix(rest, name+".") {
+				return true
+			}
+		}
+		return false
+	}
+	for _, candidate := range candidates {
+		if !within(candidate) {
+			nested = append(nested, candidate)
+		}
+	}
+	result := append([]string{}, nested...)
+	for k := range flat {
+		if !strings.HasPrefix(k, prefix+".") || k == prefix+".%" {
+			continue
+		}
+		if rest := k[len(prefix)+1:]; !within(rest) {
+			result = append(result, rest)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// childNames returns the distinct first segments of all keys below the given prefix
+func childNames(flat map[string]string, prefix string) []string {
+	names := map[string]struct{}{}
+	for k := range flat {
+		rest := k
+		if prefix != "" {
+			if !strings.HasPrefix(k, prefix+".") {
+				continue
+			}
+			rest = k[len(prefix)+1:]
+		}
+		if idx := strings.Index(rest, "."); idx >= 0 {
+			rest = rest[:idx]
+		}
+		names[rest] = struct{}{}
+	}
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
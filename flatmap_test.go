@@ -0,0 +1,104 @@
+package hcl_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+var flatmapSchema = map[string]*hcl.Schema{
+	"name":    {Type: hcl.TypeString},
+	"enabled": {Type: hcl.TypeBool},
+	"count":   {Type: hcl.TypeInt},
+	"ratio":   {Type: hcl.TypeFloat},
+	"tags":    {Type: hcl.TypeSet, Elem: &hcl.Schema{Type: hcl.TypeString}},
+	"labels":  {Type: hcl.TypeMap, Elem: &hcl.Schema{Type: hcl.TypeString}},
+	"rules": {Type: hcl.TypeList, Elem: &hcl.Resource{Schema: map[string]*hcl.Schema{
+		"name":  {Type: hcl.TypeString},
+		"value": {Type: hcl.TypeInt},
+	}}},
+}
+
+func TestFlatten(t *testing.T) {
+	properties := hcl.Properties{
+		"name":    "name",
+		"enabled": false,
+		"count":   3,
+		"ratio":   0.25,
+		"tags":    []string{"a"},
+		"labels":  map[string]interface{}{"team": "a"},
+		"rules": []interface{}{
+			map[string]interface{}{"name": "first", "value": 1},
+			map[string]interface{}{"name": "second"},
+		},
+		"other": "other",
+	}
+	flat := hcl.Flatten(properties, flatmapSchema)
+	expected := map[string]string{
+		"name":          "name",
+		"enabled":       "false",
+		"count":         "3",
+		"ratio":         "0.25",
+		"tags.#":        "1",
+		"labels.%":      "1",
+		"labels.team":   "a",
+		"rules.#":       "2",
+		"rules.0.name":  "first",
+		"rules.0.value": "1",
+		"rules.1.name":  "second",
+		"other":         "other",
+	}
+	var tagKey string
+	for k := range flat {
+		if len(k) > 5 && k[:5] == "tags." && k != "tags.#" {
+			tagKey = k
+		}
+	}
+	if _, err := strconv.Atoi(tagKey[5:]); err != nil || flat[tagKey] != "a" {
+		t.Errorf("expected tags to be addressed via hash, actual: %v", tagKey)
+	}
+	delete(flat, tagKey)
+	if !reflect.DeepEqual(flat, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, flat)
+	}
+	flat[tagKey] = "a"
+
+	unflattened, err := hcl.Unflatten(flat, flatmapSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unflattened, properties) {
+		t.Errorf("expected: %v, actual: %v", properties, unflattened)
+	}
+
+	if _, err := hcl.Unflatten(map[string]string{"count": "three"}, flatmapSchema); err == nil {
+		t.Errorf("expected: %v, actual: %v", "error", err)
+	}
+}
+
+func TestFlattenMapValues(t *testing.T) {
+	schema := map[string]*hcl.Schema{
+		"labels": {Type: hcl.TypeMap, Elem: &hcl.Schema{Type: hcl.TypeString}},
+		"groups": {Type: hcl.TypeMap, Elem: &hcl.Schema{Type: hcl.TypeList, Elem: &hcl.Schema{Type: hcl.TypeString}}},
+	}
+	properties := hcl.Properties{
+		"labels": map[string]interface{}{"app.kubernetes.io/name": "a", "team": "b"},
+		"groups": map[string]interface{}{
+			"x":       []string{"first", "second"},
+			"eu.west": []string{"third"},
+		},
+	}
+	flat := hcl.Flatten(properties, schema)
+	if flat["groups.x.#"] != "2" || flat["groups.eu.west.0"] != "third" {
+		t.Errorf("unexpected flat representation: %v", flat)
+	}
+	unflattened, err := hcl.Unflatten(flat, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unflattened, properties) {
+		t.Errorf("expected: %v, actual: %v", properties, unflattened)
+	}
+}
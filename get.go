@@ -0,0 +1,35 @@
+package hcl
+
+import "reflect"
+
+// Get returns the value stored under the given key, converted to T.
+// T may be any primitive, enum, slice, map or struct type supported by Decode and Unmarshal.
+// If the key doesn't exist the zero value of T is returned.
+// Values which cannot be converted to T result in an error instead of a panic.
+func Get[T any](d Decoder, key string) (T, error) {
+	var result T
+	err := unmarshalField(NewStrictDecoder(d), key, reflect.ValueOf(&result).Elem())
+	return result, err
+}
+
+// GetOpt returns the value stored under the given key, converted to T.
+// If the key doesn't exist nil is returned.
+func GetOpt[T any](d Decoder, key string) (*T, error) {
+	var result *T
+	err := unmarshalField(NewStrictDecoder(d), key, reflect.ValueOf(&result).Elem())
+	return result, err
+}
+
+// GetSlice returns the entries of the list or set stored under the given key, converted to T.
+func GetSlice[T any](d Decoder, key string) ([]T, error) {
+	var result []T
+	err := unmarshalField(NewStrictDecoder(d), key, reflect.ValueOf(&result).Elem())
+	return result, err
+}
+
+// GetMap returns the entries of the map stored under the given key, converted to T.
+func GetMap[T any](d Decoder, key string) (map[string]T, error) {
+	var result map[string]T
+	err := unmarshalField(NewStrictDecoder(d), key, reflect.ValueOf(&result).Elem())
+	return result, err
+}
@@ -0,0 +1,70 @@
+package hcl_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+func TestGet(t *testing.T) {
+	decoder := hcl.NewDecoder(&testDecoder{
+		Values: map[string]interface{}{
+			"name":              "name",
+			"count":             3,
+			"code":              65,
+			"ratio":             0.5,
+			"kind":              "Test",
+			"tags":              testSet{"a", "b"},
+			"limits":            map[string]interface{}{"cpu": 2},
+			"condition.#":       1,
+			"condition.0.key":   "k",
+			"condition.0.value": 1,
+		},
+	})
+	if name, err := hcl.Get[string](decoder, "name"); err != nil || name != "name" {
+		t.Errorf("expected: %v, actual: %v (%v)", "name", name, err)
+	}
+	if count, err := hcl.Get[uint8](decoder, "count"); err != nil || count != 3 {
+		t.Errorf("expected: %v, actual: %v (%v)", 3, count, err)
+	}
+	if ratio, err := hcl.GetOpt[float32](decoder, "ratio"); err != nil || ratio == nil || *ratio != 0.5 {
+		t.Errorf("expected: %v, actual: %v (%v)", 0.5, ratio, err)
+	}
+	if kind, err := hcl.GetOpt[Enum](decoder, "kind"); err != nil || kind == nil || *kind != "Test" {
+		t.Errorf("expected: %v, actual: %v (%v)", "Test", kind, err)
+	}
+	if missing, err := hcl.GetOpt[int](decoder, "missing"); err != nil || missing != nil {
+		t.Errorf("expected: %v, actual: %v (%v)", nil, missing, err)
+	}
+	if tags, err := hcl.GetSlice[Enum](decoder, "tags"); err != nil || !reflect.DeepEqual(tags, []Enum{"a", "b"}) {
+		t.Errorf("expected: %v, actual: %v (%v)", []Enum{"a", "b"}, tags, err)
+	}
+	if limits, err := hcl.GetMap[int64](decoder, "limits"); err != nil || !reflect.DeepEqual(limits, map[string]int64{"cpu": 2}) {
+		t.Errorf("expected: %v, actual: %v (%v)", map[string]int64{"cpu": 2}, limits, err)
+	}
+	if condition, err := hcl.GetOpt[Condition](decoder, "condition"); err != nil || condition == nil || condition.Key != "k" {
+		t.Errorf("expected: %v, actual: %v (%v)", "k", condition, err)
+	}
+
+	var typeError *hcl.TypeError
+	if _, err := hcl.Get[int](decoder, "name"); !errors.As(err, &typeError) {
+		t.Errorf("expected: %v, actual: %v", "*hcl.TypeError", err)
+	}
+	if _, err := hcl.Get[string](decoder, "count"); !errors.As(err, &typeError) {
+		t.Errorf("expected: %v, actual: %v", "*hcl.TypeError", err)
+	}
+	if _, err := hcl.GetSlice[bool](decoder, "tags"); !errors.As(err, &typeError) {
+		t.Errorf("expected: %v, actual: %v", "*hcl.TypeError", err)
+	}
+	if kind, err := hcl.Get[StringEnum](decoder, "code"); !errors.As(err, &typeError) || kind != "" {
+		t.Errorf("expected: %v, actual: %v (%v)", "*hcl.TypeError", kind, err)
+	}
+	if kind, err := hcl.GetOpt[StringEnum](decoder, "code"); !errors.As(err, &typeError) || kind != nil {
+		t.Errorf("expected: %v, actual: %v (%v)", "*hcl.TypeError", kind, err)
+	}
+	if small, err := hcl.Get[uint8](hcl.NewDecoder(&testDecoder{Values: map[string]interface{}{"small": 300}}), "small"); !errors.As(err, &typeError) || small != 0 {
+		t.Errorf("expected: %v, actual: %v (%v)", "*hcl.TypeError", small, err)
+	}
+}
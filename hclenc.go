@@ -0,0 +1,172 @@
+package hcl
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// encode encodes the given value as HCL expression. Strings ending with a line break and
+// spanning at least as many lines as the heredoc threshold are written as heredoc (`<<-EOT`),
+// indented by `indent`, as long as their content survives the indentation being stripped
+// again. Any other string becomes a quoted string literal. Lists are written in a single line, their strings
+// always as quoted literals. Numbers HCL has no literal for, i.e. NaN and infinite ones, produce an error.
+func (l *exportLayout) encode(v interface{}, indent string) (string, error) {
+	switch tv := v.(type) {
+	case Expr:
+		return string(tv), nil
+	case string:
+		if l.heredoc > 0 && strings.Count(strings.TrimSuffix(tv, "\n"), "\n")+1 >= l.heredoc {
+			if heredoc, ok := hclHeredoc(tv, indent, l.indent, l.trim); ok {
+				return heredoc, nil
+			}
+		}
+		return hclString(tv), nil
+	case map[string]interface{}:
+		return l.object(tv, indent)
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return "null", nil
+		}
+		return l.encode(rv.Elem().Interface(), indent)
+	case reflect.String:
+		return l.encode(rv.String(), indent)
+	case reflect.Slice, reflect.Array:
+		elems := make([]string, rv.Len())
+		for idx := range elems {
+			elem := rv.Index(idx).Interface()
+			if s, ok := elem.(string); ok {
+				elems[idx] = hclString(s)
+				continue
+			}
+			encoded, err := l.encode(elem, indent)
+			if err != nil {
+				return "", err
+			}
+			elems[idx] = encoded
+		}
+		return "[" + strings.Join(elems, listSeparator) + "]", nil
+	}
+	if v == nil {
+		return "null", nil
+	}
+	// fails for values HCL has no literal for, i.e. NaN and infinite numbers
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// listSeparator separates the elements of lists
+const listSeparator = ","
+
+// hclString encodes the given string as quoted string literal.
+//   - quotes, backslashes and line breaks are escaped the way HCL expects them
+//   - template sequences are escaped as `$${` and `%%{`
+//   - other control characters are escaped as `\uNNNN`, invalid UTF-8 is replaced with U+FFFD
+//   - any other character, including non-ASCII ones, is written as is
+func hclString(s string) string {
+	sb := new(strings.Builder)
+	sb.WriteByte('"')
+	for idx := 0; idx < len(s); {
+		r, size := utf8.DecodeRuneInString(s[idx:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			sb.WriteRune(utf8.RuneError)
+		case r == '"':
+			sb.WriteString(`\"`)
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			sb.WriteString(fmt.Sprintf(`\u%04x`, r))
+		case (r == '$' || r == '%') && strings.HasPrefix(s[idx+1:], "{"):
+			sb.WriteRune(r)
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+		idx += size
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// heredocEscaper escapes template sequences within heredocs,
+// which don't support any other escape sequences
+var heredocEscaper = strings.NewReplacer("${", "$${", "%{", "%%{")
+
+// hclHeredoc encodes multi-line strings as indented heredoc, with the lines of the
+// content indented by one more `unit` than the closing delimiter. The delimiter is
+// `EOT`, unless a line of the content collides with it, in which case it gets
+// suffixed with a counter. The line break preceding the closing delimiter is part
+// of the value, hence the final line break of the string isn't written separately.
+// Strings which cannot be represented as indented heredoc are rejected:
+//   - strings not ending with a line break
+//   - strings containing control characters other than tabs and line breaks, or invalid UTF-8
+//   - strings whose lines are all indented, because that indentation would get stripped
+//   - strings with lines ending in whitespace, in case trailing whitespace is to be trimmed
+func hclHeredoc(s string, indent string, unit string, trim bool) (string, bool) {
+	if !strings.HasSuffix(s, "\n") || !utf8.ValidString(s) {
+		return "", false
+	}
+	for _, r := range s {
+		if (r < 0x20 && r != '\n' && r != '\t') || r == 0x7f {
+			return "", false
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	indented := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			indented = false
+			break
+		}
+	}
+	if indented {
+		return "", false
+	}
+	if trim {
+		for _, line := range lines {
+			if strings.TrimRight(line, " \t") != line {
+				return "", false
+			}
+		}
+	}
+	delimiter := "EOT"
+	for idx := 1; collides(lines, delimiter); idx++ {
+		delimiter = "EOT" + strconv.Itoa(idx)
+	}
+	sb := new(strings.Builder)
+	sb.WriteString("<<-" + delimiter + "\n")
+	for _, line := range lines {
+		line = indent + unit + heredocEscaper.Replace(line)
+		if trim {
+			line = strings.TrimRight(line, " ")
+		}
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString(indent + delimiter)
+	return sb.String(), true
+}
+
+func collides(lines []string, delimiter string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == delimiter {
+			return true
+		}
+	}
+	return false
+}
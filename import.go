@@ -0,0 +1,538 @@
+package hcl
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Import parses HCL as written by Export and ExportOpt back into Properties,
+// shaped the way MarshalHCL produces them.
+// Supported are attributes, nested blocks, lists, objects (maps), heredocs and
+// attributes commented out because they hold default values (`# enabled = false`).
+// Any other expression, e.g. `var.region` or a quoted template like `"${var.prefix}-name"`,
+// is restored as Expr. Heredocs containing template sequences are rejected with an error.
+// The schema is optional. If specified, it determines whether numbers are
+// restored as int or float64. Without a schema integral numbers are restored as int.
+// Heredocs are restored including the line break preceding the closing delimiter.
+func Import(r io.Reader, schema map[string]*Schema) (Properties, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: string(data), line: 1}
+	m, err := p.parseBody(schema, false)
+	if err != nil {
+		return nil, err
+	}
+	return Properties(m), nil
+}
+
+// ImportDecoder parses HCL as written by Export and ExportOpt and returns a
+// Decoder serving the result, which can be passed straight to UnmarshalHCL.
+func ImportDecoder(r io.Reader, schema map[string]*Schema) (Decoder, error) {
+	properties, err := Import(r, schema)
+	if err != nil {
+		return nil, err
+	}
+	return PropertiesDecoder(properties), nil
+}
+
+// commentedAttribute matches comments holding an attribute, i.e. default values
+// commented out by Export
+var commentedAttribute = regexp.MustCompile(`^#\s*(?:[A-Za-z_][A-Za-z0-9_-]*|"[^"]*")\s*=`)
+
+// isCommentedAttribute reports whether the given comment line holds nothing but an
+// attribute with a literal value. Comments like `# timeout = 30 seconds` remain comments.
+func isCommentedAttribute(line string) bool {
+	if !commentedAttribute.MatchString(line) {
+		return false
+	}
+	p := &parser{src: strings.TrimPrefix(line, "#")}
+	p.skipSpace(false)
+	if p.parseIdentifier() == "" {
+		return false
+	}
+	p.skipSpace(false)
+	if p.peek() != '=' {
+		return false
+	}
+	p.advance(1)
+	p.skipSpace(false)
+	value, err := p.parseExpression(nil)
+	if err != nil {
+		return false
+	}
+	if _, isExpr := value.(Expr); isExpr {
+		return false
+	}
+	p.skipSpace(false)
+	return p.eof()
+}
+
+// templateUnescaper restores template sequences escaped within heredocs
+var templateUnescaper = strings.NewReplacer("$${", "${", "%%{", "%{")
+
+// hasTemplateSequence reports whether `s` contains a template sequence (`${` or `%{`)
+// which isn't escaped as `$${` or `%%{`
+func hasTemplateSequence(s string) bool {
+	for pos := 0; pos < len(s); pos++ {
+		switch {
+		case strings.HasPrefix(s[pos:], "$${"), strings.HasPrefix(s[pos:], "%%{"):
+			pos += 2
+		case strings.HasPrefix(s[pos:], "${"), strings.HasPrefix(s[pos:], "%{"):
+			return true
+		}
+	}
+	return false
+}
+
+type parser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) advance(n int) {
+	for i := 0; i < n && !p.eof(); i++ {
+		if p.src[p.pos] == '\n' {
+			p.line++
+		}
+		p.pos++
+	}
+}
+
+// skipSpace skips whitespace, and optionally line breaks and comments.
+// Comments holding an attribute only get their comment marker skipped.
+func (p *parser) skipSpace(newlines bool) {
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.advance(1)
+		case newlines && c == '\n':
+			p.advance(1)
+		case newlines && c == '#':
+			rest := p.src[p.pos:]
+			if idx := strings.IndexByte(rest, '\n'); idx >= 0 {
+				rest = rest[:idx]
+			}
+			if isCommentedAttribute(rest) {
+				p.advance(1)
+				continue
+			}
+			p.advance(len(rest))
+		case newlines && strings.HasPrefix(p.src[p.pos:], "//"):
+			rest := p.src[p.pos:]
+			if idx := strings.IndexByte(rest, '\n'); idx >= 0 {
+				rest = rest[:idx]
+			}
+			p.advance(len(rest))
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) parseIdentifier() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (p.pos > start && c >= '0' && c <= '9') {
+			p.advance(1)
+			continue
+		}
+		break
+	}
+	return p.src[start:p.pos]
+}
+
+// parseBody parses attributes and blocks until EOF or, if `nested`, the closing brace
+func (p *parser) parseBody(schema map[string]*Schema, nested bool) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	for {
+		p.skipSpace(true)
+		if p.eof() {
+			if nested {
+				return nil, p.errorf("unexpected end of input, expected '}'")
+			}
+			return result, nil
+		}
+		if p.peek() == '}' {
+			if !nested {
+				return nil, p.errorf("unexpected '}'")
+			}
+			p.advance(1)
+			return result, nil
+		}
+		key := p.parseIdentifier()
+		if key == "" {
+			return nil, p.errorf("unexpected character %q, expected an attribute or block name", p.peek())
+		}
+		p.skipSpace(false)
+		sch := schema[key]
+		switch p.peek() {
+		case '=':
+			p.advance(1)
+			p.skipSpace(false)
+			value, err := p.parseExpression(sch)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = value
+		case '{':
+			p.advance(1)
+			block, err := p.parseBody(elemSchema(sch), true)
+			if err != nil {
+				return nil, err
+			}
+			blocks, _ := result[key].([]interface{})
+			result[key] = append(blocks, block)
+		default:
+			return nil, p.errorf("unexpected character %q after %q, expected '=' or '{'", p.peek(), key)
+		}
+	}
+}
+
+func (p *parser) parseExpression(sch *Schema) (interface{}, error) {
+	c := p.peek()
+	switch {
+	case c == '"':
+		start := p.pos
+		value, err := p.parseString()
+		if err != nil || !hasTemplateSequence(p.src[start:p.pos]) {
+			return value, err
+		}
+		// a template can't be restored as plain string without losing its meaning
+		p.pos = start
+		return p.parseExpr()
+	case strings.HasPrefix(p.src[p.pos:], "<<"):
+		start := p.pos
+		value, err := p.parseHeredoc()
+		if err == nil && hasTemplateSequence(p.src[start:p.pos]) {
+			p.pos = start
+			return nil, p.errorf("heredoc templates are not supported")
+		}
+		return value, err
+	case c == '[':
+		return p.parseList(sch)
+	case c == '{':
+		return p.parseObject(sch)
+	case c >= '0' && c <= '9':
+		return p.parseNumber(sch)
+	case c == '-':
+		if next := p.src[p.pos+1:]; len(next) > 0 && (next[0] == '.' || (next[0] >= '0' && next[0] <= '9')) {
+			return p.parseNumber(sch)
+		}
+		return p.parseExpr()
+	}
+	start := p.pos
+	word := p.parseIdentifier()
+	p.skipSpace(false)
+	if end := p.peek(); end == 0 || end == '\n' || end == ',' || end == ']' || end == '}' || end == '#' {
+		switch word {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+	}
+	if word == "" {
+		return nil, p.errorf("unexpected character %q, expected a value", c)
+	}
+	p.pos = start
+	return p.parseExpr()
+}
+
+// parseExpr restores any expression which isn't a literal, e.g. `var.region`, as Expr
+func (p *parser) parseExpr() (Expr, error) {
+	n, err := scanExpression(p.src[p.pos:], true)
+	if err != nil {
+		return "", p.errorf("%s", err.Error())
+	}
+	expr := Expr(strings.TrimSpace(p.src[p.pos : p.pos+n]))
+	if err := expr.Validate(); err != nil {
+		return "", p.errorf("%s", err.Error())
+	}
+	p.advance(n)
+	return expr, nil
+}
+
+func (p *parser) parseString() (string, error) {
+	p.advance(1)
+	sb := new(strings.Builder)
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.peek()
+		switch {
+		case c == '"':
+			p.advance(1)
+			return sb.String(), nil
+		case c == '\\':
+			if p.pos+1 >= len(p.src) {
+				return "", p.errorf("unterminated string")
+			}
+			e := p.src[p.pos+1]
+			p.advance(2)
+			switch e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case '"', '\\', '/':
+				sb.WriteByte(e)
+			case 'u', 'U':
+				n := 4
+				if e == 'U' {
+					n = 8
+				}
+				if p.pos+n > len(p.src) {
+					return "", p.errorf("invalid unicode escape sequence")
+				}
+				code, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape sequence")
+				}
+				p.advance(n)
+				r := rune(code)
+				if r >= 0xD800 && r < 0xDC00 && strings.HasPrefix(p.src[p.pos:], "\\u") && p.pos+6 <= len(p.src) {
+					if low, err := strconv.ParseUint(p.src[p.pos+2:p.pos+6], 16, 32); err == nil && low >= 0xDC00 && low < 0xE000 {
+						r = (r-0xD800)<<10 + (rune(low) - 0xDC00) + 0x10000
+						p.advance(6)
+					}
+				}
+				sb.WriteRune(r)
+			default:
+				return "", p.errorf("invalid escape sequence \\%c", e)
+			}
+		case strings.HasPrefix(p.src[p.pos:], "$${"), strings.HasPrefix(p.src[p.pos:], "%%{"):
+			sb.WriteString(p.src[p.pos+1 : p.pos+3])
+			p.advance(3)
+		default:
+			r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+			sb.WriteRune(r)
+			p.advance(size)
+		}
+	}
+}
+
+func (p *parser) parseHeredoc() (string, error) {
+	p.advance(2)
+	indented := false
+	if p.peek() == '-' {
+		indented = true
+		p.advance(1)
+	}
+	delimiter := p.parseIdentifier()
+	if delimiter == "" {
+		return "", p.errorf("missing heredoc delimiter")
+	}
+	p.skipSpace(false)
+	if p.peek() != '\n' {
+		return "", p.errorf("expected line break after heredoc delimiter")
+	}
+	p.advance(1)
+	lines := []string{}
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated heredoc, expected %q", delimiter)
+		}
+		line := p.src[p.pos:]
+		if idx := strings.IndexByte(line, '\n'); idx >= 0 {
+			line = line[:idx]
+		}
+		p.advance(len(line))
+		if strings.TrimSpace(line) == delimiter {
+			break
+		}
+		p.advance(1)
+		lines = append(lines, strings.TrimSuffix(line, "\r"))
+	}
+	if indented {
+		lines = trimIndent(lines)
+	}
+	// every line of a heredoc, including the last one, ends with a line break
+	sb := new(strings.Builder)
+	for _, line := range lines {
+		sb.WriteString(line + "\n")
+	}
+	return templateUnescaper.Replace(sb.String()), nil
+}
+
+// trimIndent removes the leading whitespace all non-empty lines have in common
+func trimIndent(lines []string) []string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent <= 0 {
+		return lines
+	}
+	result := make([]string, len(lines))
+	for idx, line := range lines {
+		if len(line) >= indent {
+			result[idx] = line[indent:]
+		} else {
+			result[idx] = strings.TrimLeft(line, " \t")
+		}
+	}
+	return result
+}
+
+func (p *parser) parseNumber(sch *Schema) (interface{}, error) {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E' || (c >= '0' && c <= '9') {
+			p.advance(1)
+			continue
+		}
+		break
+	}
+	literal := p.src[start:p.pos]
+	if sch == nil || sch.Type != TypeFloat {
+		if value, err := strconv.Atoi(literal); err == nil {
+			return value, nil
+		}
+	}
+	value, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %q", literal)
+	}
+	return value, nil
+}
+
+func (p *parser) parseList(sch *Schema) (interface{}, error) {
+	p.advance(1)
+	var elem *Schema
+	if sch != nil {
+		elem, _ = sch.Elem.(*Schema)
+	}
+	entries := []interface{}{}
+	for {
+		p.skipSpace(true)
+		if p.eof() {
+			return nil, p.errorf("unexpected end of input, expected ']'")
+		}
+		if p.peek() == ']' {
+			p.advance(1)
+			break
+		}
+		entry, err := p.parseExpression(elem)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+		p.skipSpace(true)
+		if p.peek() == ',' {
+			p.advance(1)
+		} else if p.peek() != ']' {
+			return nil, p.errorf("unexpected character %q, expected ',' or ']'", p.peek())
+		}
+	}
+	return typedList(numbersToFloat(entries)), nil
+}
+
+func (p *parser) parseObject(sch *Schema) (interface{}, error) {
+	p.advance(1)
+	var elem *Schema
+	if sch != nil {
+		elem, _ = sch.Elem.(*Schema)
+	}
+	result := map[string]interface{}{}
+	for {
+		p.skipSpace(true)
+		if p.eof() {
+			return nil, p.errorf("unexpected end of input, expected '}'")
+		}
+		if p.peek() == '}' {
+			p.advance(1)
+			return result, nil
+		}
+		var key string
+		if p.peek() == '"' {
+			var err error
+			if key, err = p.parseString(); err != nil {
+				return nil, err
+			}
+		} else if key = p.parseIdentifier(); key == "" {
+			return nil, p.errorf("unexpected character %q, expected an object key", p.peek())
+		}
+		p.skipSpace(false)
+		if p.peek() != '=' && p.peek() != ':' {
+			return nil, p.errorf("unexpected character %q after %q, expected '='", p.peek(), key)
+		}
+		p.advance(1)
+		p.skipSpace(false)
+		value, err := p.parseExpression(elem)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+		p.skipSpace(false)
+		if p.peek() == ',' {
+			p.advance(1)
+		}
+	}
+}
+
+// numbersToFloat converts lists mixing int and float64 entries into lists of
+// float64, because Export writes integral floats without decimal places
+func numbersToFloat(entries []interface{}) []interface{} {
+	floats := false
+	for _, entry := range entries {
+		switch entry.(type) {
+		case float64:
+			floats = true
+		case int:
+		default:
+			return entries
+		}
+	}
+	if !floats {
+		return entries
+	}
+	result := make([]interface{}, len(entries))
+	for idx, entry := range entries {
+		if i, ok := entry.(int); ok {
+			result[idx] = float64(i)
+		} else {
+			result[idx] = entry
+		}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package hcl_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+func TestImport(t *testing.T) {
+	src := `  name = "dashboard" 
+  # enabled = false 
+  # deprecated: use something else
+  # timeout = 30 seconds
+  # see = the docs
+  description = <<-EOT
+    first line
+      second line
+    "quoted" $${literal}
+  EOT 
+  ratio = 1 
+  tags = ["a","b<c"] 
+  weights = [1,1.5] 
+  labels = {
+    team = "a"
+    "with space" = "b"
+    nested = {
+      key = "k"
+    }
+  }
+  conditions {
+    value = 1 
+  }
+  conditions {
+    value = 2 
+    # empty = "" 
+  }
+`
+	schema := map[string]*hcl.Schema{
+		"ratio": {Type: hcl.TypeFloat},
+	}
+	properties, err := hcl.Import(strings.NewReader(src), schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := hcl.Properties{
+		"name":        "dashboard",
+		"enabled":     false,
+		"description": "first line\n  second line\n\"quoted\" ${literal}\n",
+		"ratio":       1.0,
+		"tags":        []string{"a", "b<c"},
+		"weights":     []float64{1, 1.5},
+		"labels": map[string]interface{}{
+			"team":       "a",
+			"with space": "b",
+			"nested":     map[string]interface{}{"key": "k"},
+		},
+		"conditions": []interface{}{
+			map[string]interface{}{"value": 1},
+			map[string]interface{}{"value": 2, "empty": ""},
+		},
+	}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, properties)
+	}
+
+	for _, invalid := range []string{`name = "unterminated`, `name = {`, `block {`, `}`, `name = var.x.`, `name = f(x`, `name "x"`} {
+		if _, err := hcl.Import(strings.NewReader(invalid), nil); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestImportDecoder(t *testing.T) {
+	owner := "owner"
+	dashboard := &Dashboard{
+		Name:       "dashboard",
+		Owner:      &owner,
+		Kind:       "Test",
+		Tags:       hcl.StringSet{"a", "b"},
+		Weights:    []float64{0.5, 2},
+		Labels:     map[string]string{"team": "a"},
+		Conditions: []*failingCondition{{Value: 1}, {Value: 2}},
+		Square:     &MapContainer{Limits: map[string]int{"cpu": 2}},
+	}
+	buf := new(strings.Builder)
+	if err := hcl.Export(dashboard, buf); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := hcl.ImportDecoder(strings.NewReader(buf.String()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Dashboard{}
+	if err := decoded.UnmarshalHCL(decoder); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dashboard, decoded) {
+		t.Errorf("expected: %v, actual: %v", dashboard, decoded)
+	}
+}
@@ -0,0 +1,203 @@
+package hcl
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDialect is the `$schema` produced by JSONSchema
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema converts a `map[string]*Schema`, a `*Resource` or a Schemer into
+// a JSON Schema (draft 2020-12), ready to get passed to `json.Marshal`.
+//   - TypeBool, TypeInt, TypeFloat and TypeString map to `boolean`, `integer`, `number` and `string`
+//   - TypeList and TypeSet map to `array` (sets with `uniqueItems`), `MinItems` and `MaxItems` to `minItems` and `maxItems`
+//   - TypeMap maps to an `object` with `additionalProperties` described by `Elem`
+//   - `Elem` resources become nested objects, which don't allow for additional properties
+//   - `Default`, `Description` and `Deprecated` map to `default`, `description` and `deprecated`
+//   - attributes that are only `Computed` are `readOnly`
+//
+// `ConflictsWith`, `ExactlyOneOf`, `AtLeastOneOf` and `RequiredWith` become `not`, `oneOf`,
+// `anyOf` and `dependentRequired` constructs of the object containing the attribute.
+// JSON Schema is unable to express constraints across objects, hence only keys
+// referring to attributes of the same object are taken into account.
+func JSONSchema(v interface{}) (map[string]interface{}, error) {
+	var schema map[string]*Schema
+	switch tv := v.(type) {
+	case map[string]*Schema:
+		schema = tv
+	case *Resource:
+		if tv != nil {
+			schema = tv.Schema
+		}
+	case Schemer:
+		schema = tv.Schema()
+	default:
+		return nil, fmt.Errorf("unable to produce a JSON Schema for %T", v)
+	}
+	result := jsonSchemaObject("", schema)
+	result["$schema"] = JSONSchemaDialect
+	return result, nil
+}
+
+func jsonSchemaObject(path string, schema map[string]*Schema) map[string]interface{} {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties := map[string]interface{}{}
+	required := []string{}
+	constraints := &jsonConstraints{path: path, reported: map[string]bool{}, dependentRequired: map[string]interface{}{}}
+	for _, name := range names {
+		sch := schema[name]
+		if sch == nil {
+			continue
+		}
+		properties[name] = jsonSchemaAttribute(joinAddress(path, name), sch)
+		if sch.Required {
+			required = append(required, name)
+		}
+		constraints.add(name, sch)
+	}
+	result := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		result["required"] = required
+	}
+	if len(constraints.allOf) > 0 {
+		result["allOf"] = constraints.allOf
+	}
+	if len(constraints.dependentRequired) > 0 {
+		result["dependentRequired"] = constraints.dependentRequired
+	}
+	return result
+}
+
+func jsonSchemaAttribute(path string, sch *Schema) map[string]interface{} {
+	result := map[string]interface{}{}
+	switch sch.Type {
+	case TypeBool:
+		result["type"] = "boolean"
+	case TypeInt:
+		result["type"] = "integer"
+	case TypeFloat:
+		result["type"] = "number"
+	case TypeString:
+		result["type"] = "string"
+	case TypeList, TypeSet:
+		result["type"] = "array"
+		result["items"] = jsonSchemaElem(path, sch.Elem)
+		if sch.Type == TypeSet {
+			result["uniqueItems"] = true
+		}
+		if sch.MinItems > 0 {
+			result["minItems"] = sch.MinItems
+		}
+		if sch.MaxItems > 0 {
+			result["maxItems"] = sch.MaxItems
+		}
+	case TypeMap:
+		result["type"] = "object"
+		if sch.Elem != nil {
+			result["additionalProperties"] = jsonSchemaElem(path, sch.Elem)
+		}
+	}
+	if sch.Description != "" {
+		result["description"] = sch.Description
+	}
+	if sch.Default != nil {
+		result["default"] = sch.Default
+	}
+	if sch.Deprecated != "" {
+		result["deprecated"] = true
+		result["$comment"] = sch.Deprecated
+	}
+	if sch.Computed && !sch.Optional && !sch.Required {
+		result["readOnly"] = true
+	}
+	return result
+}
+
+func jsonSchemaElem(path string, elem interface{}) map[string]interface{} {
+	switch tElem := elem.(type) {
+	case *Resource:
+		return jsonSchemaObject(path, tElem.Schema)
+	case *Schema:
+		return jsonSchemaAttribute(path, tElem)
+	}
+	return map[string]interface{}{}
+}
+
+// jsonConstraints collects the constraints between the attributes of an object
+type jsonConstraints struct {
+	path              string
+	allOf             []interface{}
+	dependentRequired map[string]interface{}
+	reported          map[string]bool
+}
+
+func (c *jsonConstraints) add(name string, sch *Schema) {
+	for _, key := range c.siblings(sch.ConflictsWith) {
+		pair := []string{name, key}
+		sort.Strings(pair)
+		if c.once("not:" + strings.Join(pair, ",")) {
+			c.allOf = append(c.allOf, map[string]interface{}{"not": map[string]interface{}{"required": pair}})
+		}
+	}
+	if siblings := c.siblings(sch.ExactlyOneOf); len(siblings) > 0 && c.once("oneOf:"+strings.Join(siblings, ",")) {
+		c.allOf = append(c.allOf, map[string]interface{}{"oneOf": requiredEach(siblings)})
+	}
+	if siblings := c.siblings(sch.AtLeastOneOf); len(siblings) > 0 && c.once("anyOf:"+strings.Join(siblings, ",")) {
+		c.allOf = append(c.allOf, map[string]interface{}{"anyOf": requiredEach(siblings)})
+	}
+	if siblings := c.siblings(sch.RequiredWith); len(siblings) > 0 {
+		c.dependentRequired[name] = siblings
+	}
+}
+
+func (c *jsonConstraints) once(signature string) bool {
+	if c.reported[signature] {
+		return false
+	}
+	c.reported[signature] = true
+	return true
+}
+
+// siblings returns the sorted names of the attributes the given keys refer to,
+// as long as they are attributes of the object the constraints are collected for.
+// Indices within the keys are ignored, i.e. `rules.0.name` refers to `name`
+// within any block `rules`.
+func (c *jsonConstraints) siblings(keys []string) []string {
+	result := []string{}
+	for _, key := range keys {
+		segments := []string{}
+		for _, segment := range strings.Split(key, ".") {
+			if _, err := strconv.Atoi(segment); err != nil {
+				segments = append(segments, segment)
+			}
+		}
+		if len(segments) == 0 {
+			continue
+		}
+		if strings.Join(segments[:len(segments)-1], ".") == c.path {
+			result = append(result, segments[len(segments)-1])
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func requiredEach(names []string) []interface{} {
+	result := make([]interface{}, len(names))
+	for idx, name := range names {
+		result[idx] = map[string]interface{}{"required": []string{name}}
+	}
+	return result
+}
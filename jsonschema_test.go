@@ -0,0 +1,122 @@
+package hcl_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+func TestJSONSchema(t *testing.T) {
+	resource := &hcl.Resource{Schema: map[string]*hcl.Schema{
+		"name":    {Type: hcl.TypeString, Required: true, Description: "The name"},
+		"email":   {Type: hcl.TypeString, Optional: true, ExactlyOneOf: []string{"email", "webhook"}},
+		"webhook": {Type: hcl.TypeString, Optional: true, ExactlyOneOf: []string{"email", "webhook"}},
+		"id":      {Type: hcl.TypeString, Computed: true},
+		"labels":  {Type: hcl.TypeMap, Optional: true, Elem: &hcl.Schema{Type: hcl.TypeString}},
+		"rules": {Type: hcl.TypeSet, Optional: true, MinItems: 1, MaxItems: 2, Elem: &hcl.Resource{Schema: map[string]*hcl.Schema{
+			"value":  {Type: hcl.TypeFloat, Optional: true, Default: 0.5, ConflictsWith: []string{"rules.0.ratio"}},
+			"ratio":  {Type: hcl.TypeInt, Optional: true, Deprecated: "use value instead"},
+			"strict": {Type: hcl.TypeBool, Optional: true, RequiredWith: []string{"rules.0.value", "name"}},
+		}}},
+	}}
+	schema, err := hcl.JSONSchema(resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "additionalProperties": false,
+  "allOf": [
+    {
+      "oneOf": [
+        {
+          "required": [
+            "email"
+          ]
+        },
+        {
+          "required": [
+            "webhook"
+          ]
+        }
+      ]
+    }
+  ],
+  "properties": {
+    "email": {
+      "type": "string"
+    },
+    "id": {
+      "readOnly": true,
+      "type": "string"
+    },
+    "labels": {
+      "additionalProperties": {
+        "type": "string"
+      },
+      "type": "object"
+    },
+    "name": {
+      "description": "The name",
+      "type": "string"
+    },
+    "rules": {
+      "items": {
+        "additionalProperties": false,
+        "allOf": [
+          {
+            "not": {
+              "required": [
+                "ratio",
+                "value"
+              ]
+            }
+          }
+        ],
+        "dependentRequired": {
+          "strict": [
+            "value"
+          ]
+        },
+        "properties": {
+          "ratio": {
+            "$comment": "use value instead",
+            "deprecated": true,
+            "type": "integer"
+          },
+          "strict": {
+            "type": "boolean"
+          },
+          "value": {
+            "default": 0.5,
+            "type": "number"
+          }
+        },
+        "type": "object"
+      },
+      "maxItems": 2,
+      "minItems": 1,
+      "type": "array",
+      "uniqueItems": true
+    },
+    "webhook": {
+      "type": "string"
+    }
+  },
+  "required": [
+    "name"
+  ],
+  "type": "object"
+}`
+	if string(data) != expected {
+		t.Errorf("expected: %v, actual: %v", expected, string(data))
+	}
+	if _, err := hcl.JSONSchema("schema"); err == nil {
+		t.Errorf("expected: error, actual: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package hcl
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Marshal produces the Properties for the struct `v` (or the struct `v` points to).
+// Fields are mapped to attributes via struct tags of the form `hcl:"name,omitempty"`.
+//   - `omitempty` skips fields holding their zero value
+//   - `block` (the default for structs) encodes nested structs as `[]interface{}{map}`
+//   - `attr` encodes nested structs as a plain map attribute instead
+//   - `sensitive` doesn't alter the produced Properties, but marks the attribute as
+//     Sensitive in the schema AsMarshaler derives, which makes ExportOpt redact it
+//
+// Values implementing Marshaler are encoded via their own implementation.
+// The result is identical to what Properties.Encode and Properties.EncodeSlice produce.
+func Marshal(v interface{}) (Properties, error) {
+	if marshaler, ok := v.(Marshaler); ok {
+		m, err := marshaler.MarshalHCL()
+		if err != nil {
+			return nil, err
+		}
+		return Properties(m), nil
+	}
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("marshalling requires a non-nil struct. %T doesn't qualify", v)
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("marshalling requires a struct. %T doesn't qualify", v)
+	}
+	properties := Properties{}
+	for _, f := range fieldsOf(rv.Type()) {
+		fv := rv.FieldByIndex(f.Index)
+		if f.Options.Contains("omitempty") && fv.IsZero() {
+			continue
+		}
+		if err := properties.marshalField(f, fv); err != nil {
+			return nil, err
+		}
+	}
+	return properties, nil
+}
+
+func (me Properties) marshalField(f field, fv reflect.Value) error {
+	if fv.Kind() == reflect.Ptr && fv.IsNil() {
+		return nil
+	}
+	if fv.CanAddr() && fv.Kind() != reflect.Ptr && reflect.PtrTo(fv.Type()).Implements(marshalerType) {
+		fv = fv.Addr()
+	}
+	t := fv.Type()
+	switch {
+	case isStructType(t):
+		nested, err := Marshal(fv.Interface())
+		if err != nil {
+			if f.Options.Contains("attr") {
+				return encodeError(f.Name, err)
+			}
+			return encodeError(f.Name+".0", err)
+		}
+		if f.Options.Contains("attr") {
+			me[f.Name] = map[string]interface{}(nested)
+		} else {
+			me[f.Name] = []interface{}{map[string]interface{}(nested)}
+		}
+		return nil
+	case t.Kind() == reflect.Slice && isStructType(t.Elem()):
+		if fv.Len() == 0 {
+			return nil
+		}
+		if t.Elem().Implements(marshalerType) {
+			_, err := me.EncodeSlice(f.Name, fv.Interface())
+			return err
+		}
+		entries := []interface{}{}
+		for idx := 0; idx < fv.Len(); idx++ {
+			vElem := fv.Index(idx)
+			if vElem.Kind() != reflect.Ptr && reflect.PtrTo(vElem.Type()).Implements(marshalerType) {
+				vElem = vElem.Addr()
+			}
+			nested, err := Marshal(vElem.Interface())
+			if err != nil {
+				return encodeError(fmt.Sprintf("%v.%d", f.Name, idx), err)
+			}
+			entries = append(entries, map[string]interface{}(nested))
+		}
+		me[f.Name] = entries
+		return nil
+	default:
+		return me.Encode(f.Name, fv.Interface())
+	}
+}
+
+// AsMarshaler turns any struct carrying `hcl` struct tags into a Marshaler
+// based on Marshal, in order to make it eligible for Export and ExportOpt.
+// Unless `v` implements Schemer, its schema is derived via SchemaOf. Types
+// SchemaOf fails for, i.e. recursive ones, fail to marshal too.
+func AsMarshaler(v interface{}) Marshaler {
+	if marshaler, ok := v.(Marshaler); ok {
+		return marshaler
+	}
+	return &structMarshaler{v: v}
+}
+
+type structMarshaler struct {
+	v interface{}
+}
+
+func (sm *structMarshaler) MarshalHCL() (map[string]interface{}, error) {
+	if _, ok := sm.v.(Schemer); !ok {
+		if _, err := SchemaOf(sm.v); err != nil {
+			return nil, err
+		}
+	}
+	return Marshal(sm.v)
+}
+
+func (sm *structMarshaler) Schema() map[string]*Schema {
+	if schemer, ok := sm.v.(Schemer); ok {
+		return schemer.Schema()
+	}
+	schema, _ := SchemaOf(sm.v)
+	return schema
+}
@@ -1,6 +1,11 @@
 package hcl_test
 
 import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/dtcookie/hcl"
@@ -108,3 +113,134 @@ func TestMarshaller(t *testing.T) {
 
 	}
 }
+
+type Threshold struct {
+	Value float64 `hcl:"value"`
+	Unit  string  `hcl:"unit,omitempty"`
+}
+
+type Alert struct {
+	Name       string       `hcl:"name"`
+	Enabled    bool         `hcl:"enabled"`
+	Kind       StringEnum   `hcl:"kind"`
+	Priority   *int         `hcl:"priority,omitempty"`
+	Password   string       `hcl:"password,sensitive"`
+	Threshold  *Threshold   `hcl:"threshold"`
+	Thresholds []*Threshold `hcl:"thresholds"`
+	Labels     Threshold    `hcl:"labels,attr"`
+	Square     *Square      `hcl:"square,omitempty"`
+	Tags       []string     `hcl:"tags,omitempty"`
+	Ignored    string
+}
+
+func TestMarshal(t *testing.T) {
+	alert := &Alert{
+		Name:       "alert",
+		Enabled:    true,
+		Kind:       StringEnum("metric"),
+		Password:   "secret",
+		Threshold:  &Threshold{Value: 1.5, Unit: "ms"},
+		Thresholds: []*Threshold{{Value: 1}, {Value: 2, Unit: "s"}},
+		Labels:     Threshold{Value: 3},
+		Ignored:    "ignored",
+	}
+	properties, err := hcl.Marshal(alert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := hcl.Properties{
+		"name":       "alert",
+		"enabled":    true,
+		"kind":       "metric",
+		"password":   "secret",
+		"threshold":  []interface{}{map[string]interface{}{"value": 1.5, "unit": "ms"}},
+		"thresholds": []interface{}{map[string]interface{}{"value": 1.0}, map[string]interface{}{"value": 2.0, "unit": "s"}},
+		"labels":     map[string]interface{}{"value": 3.0},
+	}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, properties)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := hcl.Export(hcl.AsMarshaler(alert), buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `name = "alert"`) || !strings.Contains(buf.String(), "thresholds {") {
+		t.Errorf("unexpected export result:\n%v", buf.String())
+	}
+
+	if sch := hcl.AsMarshaler(alert).(hcl.Schemer).Schema()["password"]; sch == nil || !sch.Sensitive {
+		t.Errorf("expected: %v, actual: %v", true, sch)
+	}
+	buf = new(bytes.Buffer)
+	if err := hcl.ExportOpt(hcl.AsMarshaler(alert), buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "secret") || !strings.Contains(buf.String(), "password = ") {
+		t.Errorf("expected the password to be redacted:\n%v", buf.String())
+	}
+}
+
+type Level int
+
+type Listener struct {
+	Ports  []int   `hcl:"ports"`
+	Flags  []bool  `hcl:"flags"`
+	Levels []Level `hcl:"levels"`
+}
+
+func TestMarshalPrimitiveSlices(t *testing.T) {
+	properties, err := hcl.Marshal(&Listener{Ports: []int{1, 2}, Flags: []bool{true, false}, Levels: []Level{3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := hcl.Properties{
+		"ports":  []interface{}{1, 2},
+		"flags":  []interface{}{true, false},
+		"levels": []interface{}{3},
+	}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, properties)
+	}
+	if ports, err := hcl.GetSlice[int](hcl.PropertiesDecoder(properties), "ports"); err != nil || !reflect.DeepEqual(ports, []int{1, 2}) {
+		t.Errorf("expected: %v, actual: %v (%v)", []int{1, 2}, ports, err)
+	}
+	if levels, err := hcl.GetSlice[Level](hcl.PropertiesDecoder(properties), "levels"); err != nil || !reflect.DeepEqual(levels, []Level{3}) {
+		t.Errorf("expected: %v, actual: %v (%v)", []Level{3}, levels, err)
+	}
+
+	var encodeError *hcl.EncodeError
+	if err := (hcl.Properties{}).Encode("channel", make(chan int)); !errors.As(err, &encodeError) {
+		t.Errorf("expected: %v, actual: %v", "*hcl.EncodeError", err)
+	}
+}
+
+func TestExportNumbers(t *testing.T) {
+	buf := new(bytes.Buffer)
+	properties := rawProperties{
+		"ratio":  0.5,
+		"labels": map[string]interface{}{"ports": []interface{}{1, 2}},
+	}
+	if err := hcl.Export(properties, buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `  labels = {
+    ports = [1,2]
+  }
+  ratio = 0.5 
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+
+	var encodeError *hcl.EncodeError
+	for _, invalid := range []rawProperties{
+		{"ratio": math.NaN()},
+		{"ratios": []float64{1, math.Inf(1)}},
+		{"labels": map[string]interface{}{"ratio": math.Inf(-1)}},
+	} {
+		if err := hcl.Export(invalid, new(bytes.Buffer)); !errors.As(err, &encodeError) {
+			t.Errorf("expected: %v, actual: %v", "*hcl.EncodeError", err)
+		}
+	}
+}
@@ -118,6 +118,11 @@ func (me Properties) Marshal(decoder Decoder, key string, v interface{}) error {
 		return me.Marshal(decoder, key, *t)
 	case string:
 		me[key] = t
+	case Expr:
+		if err := t.Validate(); err != nil {
+			return encodeError(key, err)
+		}
+		me[key] = t
 	case int:
 		me[key] = t
 	case bool:
@@ -150,7 +155,7 @@ func (me Properties) Marshal(decoder Decoder, key string, v interface{}) error {
 				me[key] = []interface{}{marshalled}
 				return nil
 			} else {
-				return err
+				return encodeError(key+".0", err)
 			}
 		}
 
@@ -182,7 +187,7 @@ func (me Properties) Marshal(decoder Decoder, key string, v interface{}) error {
 func (me Properties) EncodeSlice(key string, v interface{}) (Properties, error) {
 	rv := reflect.ValueOf(v)
 	if rv.Type().Kind() != reflect.Slice {
-		return nil, fmt.Errorf("type %T is not a slice", v)
+		return nil, encodeError(key, fmt.Errorf("type %T is not a slice", v))
 	}
 	if rv.Len() == 0 {
 		return me, nil
@@ -195,10 +200,10 @@ func (me Properties) EncodeSlice(key string, v interface{}) (Properties, error)
 			if marshalled, err := marshaler.MarshalHCL(); err == nil {
 				entries = append(entries, marshalled)
 			} else {
-				return nil, err
+				return nil, encodeError(fmt.Sprintf("%v.%d", key, idx), err)
 			}
 		} else {
-			return nil, fmt.Errorf("slice entries of type %T are expected to implement hcl.Marshaler but don't", elem)
+			return nil, encodeError(fmt.Sprintf("%v.%d", key, idx), fmt.Errorf("slice entries of type %T are expected to implement hcl.Marshaler but don't", elem))
 		}
 	}
 	me[key] = entries
@@ -306,8 +311,26 @@ func (me Properties) Encode(key string, v interface{}) error {
 		} else {
 			me[key] = nil
 		}
+	case []Expr:
+		if len(t) > 0 {
+			entries := make([]interface{}, len(t))
+			for idx, expr := range t {
+				if err := expr.Validate(); err != nil {
+					return encodeError(fmt.Sprintf("%v.%d", key, idx), err)
+				}
+				entries[idx] = expr
+			}
+			me[key] = entries
+		} else {
+			me[key] = nil
+		}
 	case string:
 		me[key] = t
+	case Expr:
+		if err := t.Validate(); err != nil {
+			return encodeError(key, err)
+		}
+		me[key] = t
 	case int:
 		me[key] = t
 	case bool:
@@ -340,7 +363,7 @@ func (me Properties) Encode(key string, v interface{}) error {
 		}
 		data, err := json.Marshal(t)
 		if err != nil {
-			return err
+			return encodeError(key, err)
 		}
 		me["unknowns"] = string(data)
 	default:
@@ -356,17 +379,37 @@ func (me Properties) Encode(key string, v interface{}) error {
 				}
 				me[key] = entries
 				return nil
-			} else if reflect.TypeOf(v).Elem().Kind() == reflect.Float64 {
+			} else if kind := reflect.TypeOf(v).Elem().Kind(); kind == reflect.Float64 || kind == reflect.Float32 {
 				entries := []float64{}
 				vValue := reflect.ValueOf(v)
 				for i := 0; i < vValue.Len(); i++ {
-					entries = append(entries, vValue.Index(i).Interface().(float64))
+					entries = append(entries, vValue.Index(i).Float())
+				}
+				me[key] = entries
+				return nil
+			} else if primitiveType(reflect.TypeOf(v).Elem()) != TypeInvalid {
+				// bools and numbers, including enums based on them, are stored the
+				// way Encode stores single values of them
+				entries := []interface{}{}
+				vValue := reflect.ValueOf(v)
+				for i := 0; i < vValue.Len(); i++ {
+					switch vElem := vValue.Index(i); vElem.Kind() {
+					case reflect.Bool:
+						entries = append(entries, vElem.Bool())
+					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+						entries = append(entries, int(vElem.Int()))
+					default:
+						entries = append(entries, int(vElem.Uint()))
+					}
 				}
 				me[key] = entries
 				return nil
 			}
 
 		}
+		if reflect.TypeOf(v).Kind() == reflect.Map && reflect.TypeOf(v).Key().Kind() == reflect.String {
+			return me.encodeMap(key, reflect.ValueOf(v))
+		}
 		if reflect.TypeOf(v).Kind() == reflect.String {
 			me[key] = fmt.Sprintf("%v", v)
 			return nil
@@ -378,7 +421,7 @@ func (me Properties) Encode(key string, v interface{}) error {
 				me[key] = []interface{}{marshalled}
 				return nil
 			} else {
-				return err
+				return encodeError(key+".0", err)
 			}
 		} else if marshaller, ok := v.(Marshaler); ok {
 			if reflect.ValueOf(v).IsNil() {
@@ -388,7 +431,7 @@ func (me Properties) Encode(key string, v interface{}) error {
 				me[key] = []interface{}{marshalled}
 				return nil
 			} else {
-				return err
+				return encodeError(key+".0", err)
 			}
 
 		} else if reflect.TypeOf(v).Kind() == reflect.Ptr {
@@ -406,7 +449,45 @@ func (me Properties) Encode(key string, v interface{}) error {
 				return me.Encode(key, reflect.ValueOf(v).Elem().Interface())
 			}
 		}
-		panic(fmt.Sprintf("unsupported type %T", v))
+		return encodeError(key, fmt.Errorf("unsupported type %T", v))
+	}
+	return nil
+}
+
+// encodeMap stores maps with string keys as `map[string]interface{}`.
+// Primitive values are stored the way Encode stores them, values implementing
+// Marshaler (or structs carrying `hcl` struct tags) are stored as nested maps.
+func (me Properties) encodeMap(key string, vMap reflect.Value) error {
+	if vMap.Len() == 0 {
+		return nil
+	}
+	entries := Properties{}
+	iter := vMap.MapRange()
+	for iter.Next() {
+		name := iter.Key().String()
+		value := iter.Value().Interface()
+		if marshaler, ok := value.(Marshaler); ok {
+			if reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil() {
+				continue
+			}
+			marshalled, err := marshaler.MarshalHCL()
+			if err != nil {
+				return encodeError(key+"."+name, err)
+			}
+			entries[name] = marshalled
+		} else if isStructType(iter.Value().Type()) {
+			if reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil() {
+				continue
+			}
+			marshalled, err := Marshal(value)
+			if err != nil {
+				return encodeError(key+"."+name, err)
+			}
+			entries[name] = map[string]interface{}(marshalled)
+		} else if err := entries.Encode(name, value); err != nil {
+			return encodeError(key, err)
+		}
 	}
+	me[key] = map[string]interface{}(entries)
 	return nil
 }
@@ -0,0 +1,103 @@
+package hcl_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type Dashboard struct {
+	Name       string
+	Enabled    bool
+	Owner      *string
+	Kind       Enum
+	Tags       hcl.StringSet
+	Weights    []float64
+	Labels     map[string]string
+	Conditions []*failingCondition
+	Square     *MapContainer
+}
+
+func (me *Dashboard) MarshalHCL() (map[string]interface{}, error) {
+	properties := hcl.Properties{}
+	if _, err := properties.EncodeSlice("conditions", me.Conditions); err != nil {
+		return nil, err
+	}
+	return properties.EncodeAll(map[string]interface{}{
+		"name":    me.Name,
+		"enabled": me.Enabled,
+		"owner":   me.Owner,
+		"kind":    me.Kind,
+		"tags":    me.Tags,
+		"weights": me.Weights,
+		"labels":  me.Labels,
+		"square":  me.Square,
+	})
+}
+
+func (me *Dashboard) UnmarshalHCL(decoder hcl.Decoder) error {
+	if err := decoder.DecodeSlice("conditions", &me.Conditions); err != nil {
+		return err
+	}
+	return decoder.DecodeAll(map[string]interface{}{
+		"name":    &me.Name,
+		"enabled": &me.Enabled,
+		"owner":   &me.Owner,
+		"kind":    &me.Kind,
+		"tags":    &me.Tags,
+		"weights": &me.Weights,
+		"labels":  &me.Labels,
+		"square":  &me.Square,
+	})
+}
+
+func TestPropertiesDecoder(t *testing.T) {
+	owner := "owner"
+	dashboard := &Dashboard{
+		Name:       "dashboard",
+		Owner:      &owner,
+		Kind:       "Test",
+		Tags:       hcl.StringSet{"a", "b"},
+		Weights:    []float64{0.5},
+		Labels:     map[string]string{"team": "a"},
+		Conditions: []*failingCondition{{Value: 1}, {Value: 2}},
+		Square:     &MapContainer{Limits: map[string]int{"cpu": 2}},
+	}
+	properties, err := dashboard.MarshalHCL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := hcl.PropertiesDecoder(properties)
+	if count, ok := decoder.GetOk("conditions.#"); !ok || count != 2 {
+		t.Errorf("expected: %v, actual: %v", 2, count)
+	}
+	if count, ok := decoder.GetOk("labels.%"); !ok || count != 1 {
+		t.Errorf("expected: %v, actual: %v", 1, count)
+	}
+	if value, ok := decoder.GetOk("conditions.1.value"); !ok || value != 2 {
+		t.Errorf("expected: %v, actual: %v", 2, value)
+	}
+	if enabled, ok := decoder.GetOkExists("enabled"); !ok || enabled != false {
+		t.Errorf("expected: %v, actual: %v", false, enabled)
+	}
+	if tags, ok := decoder.GetOk("tags"); !ok {
+		t.Errorf("expected: %v, actual: %v", "hcl.Set", tags)
+	} else if _, ok := tags.(hcl.Set); !ok {
+		t.Errorf("expected: %v, actual: %T", "hcl.Set", tags)
+	}
+	if tags := decoder.GetStringSet("tags"); !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("expected: %v, actual: %v", []string{"a", "b"}, tags)
+	}
+	if _, ok := decoder.GetOk("missing.0.value"); ok {
+		t.Errorf("expected: %v, actual: %v", false, ok)
+	}
+
+	decoded := &Dashboard{}
+	if err := decoded.UnmarshalHCL(decoder); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dashboard, decoded) {
+		t.Errorf("expected: %v, actual: %v", dashboard, decoded)
+	}
+}
@@ -2,6 +2,7 @@ package hcl
 
 import (
 	"encoding/json"
+	"reflect"
 
 	"github.com/dtcookie/opt"
 )
@@ -12,10 +13,33 @@ type Reader interface {
 	Int32(string) *int32
 	Bool(string) *bool
 	Count(string) int
+	// Decode populates the struct `v` points to based on its `hcl` struct tags
 	Decode(v interface{}) error
 }
 
-func NewReader(decoder Decoder, unknowns map[string]json.RawMessage) Reader {
+// TypedReader extends Reader with accessors for every numeric width, string and enum
+// slices and nested blocks. The Readers returned by NewReader and Decoder.Reader implement it.
+type TypedReader interface {
+	Reader
+	Strings(string) []string
+	Float32(string) *float32
+	Int(string) *int
+	Int8(string) *int8
+	Int16(string) *int16
+	Int64(string) *int64
+	Uint(string) *uint
+	Uint8(string) *uint8
+	Uint16(string) *uint16
+	Uint32(string) *uint32
+	Uint64(string) *uint64
+	// Enums decodes the list or set stored under the given key into
+	// a pointer to a slice of a string based enum type
+	Enums(key string, v interface{}) error
+	// Child returns a Reader for the entry `idx` of the block stored under the given key
+	Child(key string, idx int) TypedReader
+}
+
+func NewReader(decoder Decoder, unknowns map[string]json.RawMessage) TypedReader {
 	return &reader{decoder: decoder, unknowns: unknowns}
 }
 
@@ -38,10 +62,95 @@ func (r *reader) String(key string) *string {
 	return nil
 }
 
-func (r *reader) Int32(key string) *int32 {
+func (r *reader) Strings(key string) []string {
+	r.rmk(key)
+	if _, ok := r.decoder.GetOk(key); ok {
+		return r.decoder.GetStringSet(key)
+	}
+	return nil
+}
+
+func (r *reader) int(key string) (int, bool) {
 	r.rmk(key)
 	if value, _ := r.decoder.GetOk(key); value != nil {
-		return opt.NewInt32(int32(value.(int)))
+		return value.(int), true
+	}
+	return 0, false
+}
+
+func (r *reader) Int(key string) *int {
+	if value, ok := r.int(key); ok {
+		return opt.NewInt(value)
+	}
+	return nil
+}
+
+func (r *reader) Int8(key string) *int8 {
+	if value, ok := r.int(key); ok {
+		return opt.NewInt8(int8(value))
+	}
+	return nil
+}
+
+func (r *reader) Int16(key string) *int16 {
+	if value, ok := r.int(key); ok {
+		return opt.NewInt16(int16(value))
+	}
+	return nil
+}
+
+func (r *reader) Int32(key string) *int32 {
+	if value, ok := r.int(key); ok {
+		return opt.NewInt32(int32(value))
+	}
+	return nil
+}
+
+func (r *reader) Int64(key string) *int64 {
+	if value, ok := r.int(key); ok {
+		return opt.NewInt64(int64(value))
+	}
+	return nil
+}
+
+func (r *reader) Uint(key string) *uint {
+	if value, ok := r.int(key); ok {
+		return opt.NewUint(uint(value))
+	}
+	return nil
+}
+
+func (r *reader) Uint8(key string) *uint8 {
+	if value, ok := r.int(key); ok {
+		return opt.NewUInt8(uint8(value))
+	}
+	return nil
+}
+
+func (r *reader) Uint16(key string) *uint16 {
+	if value, ok := r.int(key); ok {
+		return opt.NewUInt16(uint16(value))
+	}
+	return nil
+}
+
+func (r *reader) Uint32(key string) *uint32 {
+	if value, ok := r.int(key); ok {
+		return opt.NewUInt32(uint32(value))
+	}
+	return nil
+}
+
+func (r *reader) Uint64(key string) *uint64 {
+	if value, ok := r.int(key); ok {
+		return opt.NewUInt64(uint64(value))
+	}
+	return nil
+}
+
+func (r *reader) Float32(key string) *float32 {
+	if value := r.Float64(key); value != nil {
+		return opt.NewFloat32(float32(*value))
 	}
 	return nil
 }
@@ -70,6 +179,21 @@ func (r *reader) Count(key string) int {
 	return 0
 }
 
+func (r *reader) Enums(key string, v interface{}) error {
+	r.rmk(key)
+	return r.decoder.Decode(key, v)
+}
+
+func (r *reader) Child(key string, idx int) TypedReader {
+	r.rmk(key)
+	return NewReader(NewDecoder(r.decoder, key, idx), nil)
+}
+
 func (r *reader) Decode(v interface{}) error {
-	return nil
+	if rv := reflect.ValueOf(v); rv.IsValid() && rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct {
+		for _, f := range fieldsOf(rv.Elem().Type()) {
+			r.rmk(f.Name)
+		}
+	}
+	return unmarshal(r.decoder, v)
 }
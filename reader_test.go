@@ -0,0 +1,62 @@
+package hcl_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+func TestReader(t *testing.T) {
+	unknowns := map[string]json.RawMessage{
+		"name":       json.RawMessage(`"rule"`),
+		"kind":       json.RawMessage(`"Test"`),
+		"conditions": json.RawMessage(`[]`),
+		"retries":    json.RawMessage(`3`),
+		"other":      json.RawMessage(`true`),
+	}
+	decoder := hcl.NewDecoder(&testDecoder{
+		Values: map[string]interface{}{
+			"retries":            3,
+			"kinds":              testSet{"A", "B"},
+			"conditions.#":       1,
+			"conditions.0.key":   "first",
+			"conditions.0.value": 5,
+		},
+	})
+	reader := decoder.Reader(unknowns).(hcl.TypedReader)
+	if retries := reader.Uint16("retries"); retries == nil || *retries != 3 {
+		t.Errorf("expected: %v, actual: %v", 3, retries)
+	}
+	if missing := reader.Int64("missing"); missing != nil {
+		t.Errorf("expected: %v, actual: %v", nil, missing)
+	}
+	kinds := []Enum{}
+	if err := reader.Enums("kinds", &kinds); err != nil {
+		t.Error(err)
+	}
+	if len(kinds) != 2 || kinds[1] != "B" {
+		t.Errorf("expected: %v, actual: %v", []Enum{"A", "B"}, kinds)
+	}
+	child := reader.Child("conditions", 0)
+	if key := child.String("key"); key == nil || *key != "first" {
+		t.Errorf("expected: %v, actual: %v", "first", key)
+	}
+	if value := child.Int8("value"); value == nil || *value != 5 {
+		t.Errorf("expected: %v, actual: %v", 5, value)
+	}
+
+	rule := &Rule{}
+	if err := hcl.NewDecoder(&testDecoder{Values: map[string]interface{}{"name": "rule", "kind": "Test"}}).Reader(unknowns).Decode(rule); err != nil {
+		t.Error(err)
+	}
+	if rule.Name != "rule" || rule.Kind != "Test" {
+		t.Errorf("expected: %v, actual: %v", "rule/Test", rule)
+	}
+	if len(unknowns) != 1 {
+		t.Errorf("expected: %v, actual: %v", 1, len(unknowns))
+	}
+	if _, found := unknowns["other"]; !found {
+		t.Errorf("expected %v to remain unknown", "other")
+	}
+}
@@ -0,0 +1,84 @@
+package hcl
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Difference describes an attribute whose value didn't survive a round trip
+type Difference struct {
+	Path     string
+	Expected interface{}
+	Actual   interface{}
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: expected %#v, actual %#v", d.Path, d.Expected, d.Actual)
+}
+
+// RoundTripError is returned by RoundTrip in case the decoded object
+// differs from the original one. HCL contains the exported configuration.
+type RoundTripError struct {
+	HCL         string
+	Differences []Difference
+}
+
+func (e *RoundTripError) Error() string {
+	diffs := make([]string, len(e.Differences))
+	for idx, diff := range e.Differences {
+		diffs[idx] = diff.String()
+	}
+	return fmt.Sprintf("round trip produced %d difference(s): %s", len(e.Differences), strings.Join(diffs, "; "))
+}
+
+// RoundTrip verifies that the given object survives being exported via ExportOpt,
+// parsed back via Import and decoded via UnmarshalHCL into a fresh instance.
+// The original and the fresh instance are compared based on the results of their
+// MarshalHCL methods. Any mismatch is reported as RoundTripError.
+// `v` is expected to be a pointer, because a fresh instance of the type it points
+// to is required for decoding.
+func RoundTrip(v interface {
+	Marshaler
+	Unmarshaler
+}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("round trips require a non-nil pointer. %T doesn't qualify", v)
+	}
+	expected, err := v.MarshalHCL()
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	if err := ExportOpt(v, buf, &ExportOptions{Sensitive: SensitiveVerbatim}); err != nil {
+		return err
+	}
+	var schema map[string]*Schema
+	if schemer, ok := v.(Schemer); ok {
+		schema = schemer.Schema()
+	}
+	decoder, err := ImportDecoder(bytes.NewReader(buf.Bytes()), schema)
+	if err != nil {
+		return fmt.Errorf("failed to parse exported HCL: %w", err)
+	}
+	fresh := reflect.New(rv.Type().Elem()).Interface()
+	if err := fresh.(Unmarshaler).UnmarshalHCL(decoder); err != nil {
+		return err
+	}
+	actual, err := fresh.(Marshaler).MarshalHCL()
+	if err != nil {
+		return err
+	}
+	differences := []Difference{}
+	walkDiff("", expected, actual, func(path string, expected interface{}, actual interface{}) {
+		differences = append(differences, Difference{Path: path, Expected: expected, Actual: actual})
+	})
+	if len(differences) == 0 {
+		return nil
+	}
+	sort.Slice(differences, func(i, j int) bool { return differences[i].Path < differences[j].Path })
+	return &RoundTripError{HCL: buf.String(), Differences: differences}
+}
@@ -0,0 +1,182 @@
+package hcl_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type Severity string
+
+type Notification struct {
+	Name        string
+	Description string
+	Enabled     bool
+	Muted       bool
+	Severity    Severity
+	Severities  []Severity
+	Recipients  hcl.StringSet
+	Threshold   float64
+	Retries     int
+	Labels      map[string]string
+	Filters     []*Filter
+}
+
+func (me *Notification) Schema() map[string]*hcl.Schema {
+	return map[string]*hcl.Schema{
+		"name":        {Type: hcl.TypeString, Required: true},
+		"description": {Type: hcl.TypeString, Optional: true},
+		"enabled":     {Type: hcl.TypeBool, Optional: true},
+		"muted":       {Type: hcl.TypeBool, Optional: true},
+		"severity":    {Type: hcl.TypeString, Required: true},
+		"severities":  {Type: hcl.TypeSet, Optional: true, Elem: &hcl.Schema{Type: hcl.TypeString}},
+		"recipients":  {Type: hcl.TypeSet, Optional: true, Elem: &hcl.Schema{Type: hcl.TypeString}},
+		"threshold":   {Type: hcl.TypeFloat, Optional: true},
+		"retries":     {Type: hcl.TypeInt, Optional: true},
+		"labels":      {Type: hcl.TypeMap, Optional: true, Elem: &hcl.Schema{Type: hcl.TypeString}},
+		"filter":      {Type: hcl.TypeList, Optional: true, Elem: &hcl.Resource{Schema: new(Filter).Schema()}},
+	}
+}
+
+func (me *Notification) MarshalHCL() (map[string]interface{}, error) {
+	properties := hcl.Properties{}
+	if _, err := properties.EncodeSlice("filter", me.Filters); err != nil {
+		return nil, err
+	}
+	return properties.EncodeAll(map[string]interface{}{
+		"name":        me.Name,
+		"description": me.Description,
+		"enabled":     me.Enabled,
+		"muted":       me.Muted,
+		"severity":    me.Severity,
+		"severities":  me.Severities,
+		"recipients":  me.Recipients,
+		"threshold":   me.Threshold,
+		"retries":     me.Retries,
+		"labels":      me.Labels,
+	})
+}
+
+func (me *Notification) UnmarshalHCL(decoder hcl.Decoder) error {
+	if err := decoder.DecodeSlice("filter", &me.Filters); err != nil {
+		return err
+	}
+	return decoder.DecodeAll(map[string]interface{}{
+		"name":        &me.Name,
+		"description": &me.Description,
+		"enabled":     &me.Enabled,
+		"muted":       &me.Muted,
+		"severity":    &me.Severity,
+		"severities":  &me.Severities,
+		"recipients":  &me.Recipients,
+		"threshold":   &me.Threshold,
+		"retries":     &me.Retries,
+		"labels":      &me.Labels,
+	})
+}
+
+type Filter struct {
+	Key      string
+	Negate   bool
+	Values   []string
+	Children []*Filter
+}
+
+func (me *Filter) Schema() map[string]*hcl.Schema {
+	return map[string]*hcl.Schema{
+		"key":    {Type: hcl.TypeString, Required: true},
+		"negate": {Type: hcl.TypeBool, Optional: true},
+		"values": {Type: hcl.TypeList, Optional: true, Elem: &hcl.Schema{Type: hcl.TypeString}},
+		"child": {Type: hcl.TypeList, Optional: true, Elem: &hcl.Resource{Schema: map[string]*hcl.Schema{
+			"key":    {Type: hcl.TypeString, Required: true},
+			"negate": {Type: hcl.TypeBool, Optional: true},
+		}}},
+	}
+}
+
+func (me *Filter) MarshalHCL() (map[string]interface{}, error) {
+	properties := hcl.Properties{}
+	if _, err := properties.EncodeSlice("child", me.Children); err != nil {
+		return nil, err
+	}
+	return properties.EncodeAll(map[string]interface{}{
+		"key":    me.Key,
+		"negate": me.Negate,
+		"values": me.Values,
+	})
+}
+
+func (me *Filter) UnmarshalHCL(decoder hcl.Decoder) error {
+	if err := decoder.DecodeSlice("child", &me.Children); err != nil {
+		return err
+	}
+	return decoder.DecodeAll(map[string]interface{}{
+		"key":    &me.Key,
+		"negate": &me.Negate,
+		"values": &me.Values,
+	})
+}
+
+// lossyNotification forgets about its description while decoding
+type lossyNotification struct {
+	Notification
+}
+
+func (me *lossyNotification) UnmarshalHCL(decoder hcl.Decoder) error {
+	if err := me.Notification.UnmarshalHCL(decoder); err != nil {
+		return err
+	}
+	me.Description = ""
+	return nil
+}
+
+func TestRoundTrip(t *testing.T) {
+	notifications := map[string]*Notification{
+		"minimal": {Name: "minimal", Severity: "LOW"},
+		"defaults": {
+			Name:        "defaults",
+			Description: "",
+			Enabled:     false,
+			Severity:    "HIGH",
+		},
+		"heredoc": {
+			Name:        "heredoc",
+			Description: "first line\n  indented line\n\nlast line with \"quotes\" and <html> & ${template}\n",
+			Severity:    "HIGH",
+		},
+		"full": {
+			Name:       "full",
+			Enabled:    true,
+			Muted:      true,
+			Severity:   "MEDIUM",
+			Severities: []Severity{"LOW", "HIGH"},
+			Recipients: hcl.StringSet{"a@example.com", "b@example.com"},
+			Threshold:  2,
+			Retries:    3,
+			Labels:     map[string]string{"team": "a", "with space": "b"},
+			Filters: []*Filter{
+				{Key: "first", Values: []string{"x", "y"}},
+				{Key: "second", Negate: true, Children: []*Filter{{Key: "nested"}, {Key: "negated", Negate: true}}},
+			},
+		},
+	}
+	for name, notification := range notifications {
+		if err := hcl.RoundTrip(notification); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	lossy := &lossyNotification{Notification: Notification{Name: "lossy", Description: "lost", Severity: "LOW"}}
+	err := hcl.RoundTrip(lossy)
+	var roundTripError *hcl.RoundTripError
+	if !errors.As(err, &roundTripError) {
+		t.Fatalf("expected: %v, actual: %v", "*hcl.RoundTripError", err)
+	}
+	if len(roundTripError.Differences) != 1 {
+		t.Fatalf("expected: %v, actual: %v", 1, roundTripError.Differences)
+	}
+	if diff := roundTripError.Differences[0]; diff.Path != "description" || diff.Expected != "lost" || diff.Actual != "" {
+		t.Errorf("unexpected difference: %v", diff)
+	}
+}
@@ -17,6 +17,10 @@ type Schema struct {
 	AtLeastOneOf  []string
 	RequiredWith  []string
 	ForceNew      bool
+	// Index is the position the attribute has been declared at within its schema,
+	// starting with 1. SchemaOf fills it in, ExportOptions.Order OrderSchema honours it.
+	// Hand-written schemas need to set it themselves in order to be written in declaration order.
+	Index int
 }
 
 type ValueType int
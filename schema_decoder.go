@@ -0,0 +1,230 @@
+package hcl
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaDecoder serves the `Default` of the schema for attributes
+// its parent doesn't contain and records warnings for deprecated
+// attributes which are set
+type schemaDecoder struct {
+	parent MinDecoder
+	schema map[string]*Schema
+	diags  Diagnostics
+	warned map[string]bool
+}
+
+// NewSchemaDecoder produces a Decoder which falls back to the `Default` values
+// of the given schema for attributes missing in `parent`, including attributes
+// of nested `Elem` resources. Defaults of attributes within blocks are only
+// applied if the block itself exists. Like configured values, defaults which
+// are zero values are reported as not set by GetOk, but as set by GetOkExists.
+// Whether a value originates from the configuration or from the default can be
+// checked via FromDefault.
+// Attributes flagged as `Deprecated` which are set produce warnings,
+// available via DiagnosticsOf.
+func NewSchemaDecoder(parent MinDecoder, schema map[string]*Schema) Decoder {
+	return NewDecoder(&schemaDecoder{parent: parent, schema: schema, warned: map[string]bool{}})
+}
+
+// DiagnosticsOf returns the warnings of a Decoder produced by NewSchemaDecoder
+// (or derived from one via NewDecoder). Deprecated attributes are reported if
+// they are set, regardless of whether they have been read while decoding.
+// For any other Decoder the result is empty.
+func DiagnosticsOf(d MinDecoder) Diagnostics {
+	switch td := d.(type) {
+	case *decoder:
+		return DiagnosticsOf(td.parent)
+	case *mindecoder:
+		return DiagnosticsOf(td.parent)
+	case *schemaDecoder:
+		td.scanDeprecated("", td.schema)
+		return td.diags
+	}
+	return nil
+}
+
+// scanDeprecated walks the schema along the values of the parent and records
+// warnings for deprecated attributes which are set, including the ones within blocks
+func (sd *schemaDecoder) scanDeprecated(address string, schema map[string]*Schema) {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sch := schema[name]
+		key := joinAddress(address, name)
+		if sch.Deprecated != "" {
+			if _, exists := sd.parent.GetOkExists(key); exists {
+				sd.checkDeprecated(key)
+			}
+			continue
+		}
+		resource, ok := sch.Elem.(*Resource)
+		if !ok {
+			continue
+		}
+		value, ok := sd.parent.GetOk(key)
+		if !ok {
+			continue
+		}
+		if set, ok := value.(Set); ok {
+			for _, entry := range set.List() {
+				sd.scanDeprecated(joinAddress(key, fmt.Sprintf("%v", setHash(set, entry))), resource.Schema)
+			}
+			continue
+		}
+		if count, ok := sd.parent.GetOk(key + ".#"); ok {
+			n, _ := count.(int)
+			for idx := 0; idx < n; idx++ {
+				sd.scanDeprecated(joinAddress(key, fmt.Sprintf("%d", idx)), resource.Schema)
+			}
+		}
+	}
+}
+
+// FromDefault reports whether the value the given Decoder returns for `key`
+// is the `Default` of the schema rather than a configured value.
+// Only Decoders produced by NewSchemaDecoder (or derived from one via NewDecoder)
+// are able to serve defaults. For any other Decoder the result is false.
+func FromDefault(d MinDecoder, key string) bool {
+	switch td := d.(type) {
+	case *decoder:
+		return FromDefault(td.parent, joinAddress(td.address, key))
+	case *mindecoder:
+		return FromDefault(td.parent, key)
+	case *schemaDecoder:
+		_, found := td.defaultOf(key)
+		return found
+	}
+	return false
+}
+
+// defaultOf returns the default for the given key, as long as it's absent
+// from the parent and the block containing it exists
+func (sd *schemaDecoder) defaultOf(key string) (interface{}, bool) {
+	sch := schemaAt(sd.schema, key)
+	if sch == nil || sch.Default == nil {
+		return nil, false
+	}
+	if sd.exists(key) {
+		return nil, false
+	}
+	segments := strings.Split(key, ".")
+	for idx := 1; idx < len(segments)-1; idx += 2 {
+		if !sd.blockExists(strings.Join(segments[:idx], "."), segments[idx]) {
+			return nil, false
+		}
+	}
+	return sch.Default, true
+}
+
+// exists reports whether the parent contains a value for the given key
+func (sd *schemaDecoder) exists(key string) bool {
+	if _, ok := sd.parent.GetOk(key); ok {
+		return true
+	}
+	_, ok := sd.parent.GetOkExists(key)
+	return ok
+}
+
+// blockExists reports whether the parent contains the entry `index` of the blocks
+// stored under the given key. For lists the index is checked against the count
+// (`key.#`), for sets it is expected to be the hash of one of the entries.
+func (sd *schemaDecoder) blockExists(key string, index string) bool {
+	if count, ok := sd.parent.GetOk(key + ".#"); ok {
+		if n, ok := count.(int); ok {
+			if idx, err := strconv.Atoi(index); err == nil && idx >= 0 && idx < n {
+				return true
+			}
+		}
+	}
+	if value, ok := sd.parent.GetOk(key); ok {
+		if set, ok := value.(Set); ok {
+			for _, entry := range set.List() {
+				if fmt.Sprintf("%v", setHash(set, entry)) == index {
+					return true
+				}
+			}
+		}
+	}
+	return sd.exists(key + "." + index)
+}
+
+// schemaAt resolves the schema of the attribute addressed by the given key,
+// e.g. `rules.0.name`. The segments following blocks are expected to be indices
+// (or hashes) and therefore ignored.
+func schemaAt(schema map[string]*Schema, key string) *Schema {
+	segments := strings.Split(key, ".")
+	for idx := 0; idx < len(segments); idx += 2 {
+		sch := schema[segments[idx]]
+		if sch == nil {
+			return nil
+		}
+		if idx == len(segments)-1 {
+			return sch
+		}
+		resource, ok := sch.Elem.(*Resource)
+		if !ok {
+			return nil
+		}
+		schema = resource.Schema
+	}
+	return nil
+}
+
+// checkDeprecated records a warning the first time a deprecated attribute is found to be set.
+// Counts of lists (`key.#`) and maps (`key.%`) are attributed to the attribute itself.
+func (sd *schemaDecoder) checkDeprecated(key string) {
+	key = strings.TrimSuffix(strings.TrimSuffix(key, ".#"), ".%")
+	if sd.warned[key] {
+		return
+	}
+	if sch := schemaAt(sd.schema, key); sch != nil && sch.Deprecated != "" {
+		sd.warned[key] = true
+		sd.diags = append(sd.diags, Diagnostic{Severity: SeverityWarning, Path: key, Summary: sch.Deprecated})
+	}
+}
+
+func (sd *schemaDecoder) GetOk(key string) (interface{}, bool) {
+	if value, ok := sd.parent.GetOk(key); ok {
+		sd.checkDeprecated(key)
+		return value, ok
+	}
+	if value, ok := sd.defaultOf(key); ok {
+		// like for configured values, zero values are reported as not set
+		return value, !reflect.ValueOf(value).IsZero()
+	}
+	return sd.parent.GetOk(key)
+}
+
+func (sd *schemaDecoder) GetOkExists(key string) (interface{}, bool) {
+	if value, ok := sd.parent.GetOkExists(key); ok {
+		sd.checkDeprecated(key)
+		return value, ok
+	}
+	return sd.defaultOf(key)
+}
+
+func (sd *schemaDecoder) Get(key string) interface{} {
+	if value, ok := sd.defaultOf(key); ok {
+		return value
+	}
+	if _, ok := sd.parent.GetOk(key); ok {
+		sd.checkDeprecated(key)
+	}
+	return sd.parent.Get(key)
+}
+
+func (sd *schemaDecoder) GetChange(key string) (interface{}, interface{}) {
+	return sd.parent.GetChange(key)
+}
+
+func (sd *schemaDecoder) HasChange(key string) bool {
+	return sd.parent.HasChange(key)
+}
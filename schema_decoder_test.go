@@ -0,0 +1,112 @@
+package hcl_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type Retry struct {
+	Attempts int     `hcl:"attempts"`
+	Backoff  float64 `hcl:"backoff"`
+}
+
+type Job struct {
+	Name    string   `hcl:"name"`
+	Timeout int      `hcl:"timeout"`
+	Mode    string   `hcl:"mode"`
+	Retries []*Retry `hcl:"retries"`
+	Retry   *Retry   `hcl:"retry"`
+}
+
+var jobSchema = map[string]*hcl.Schema{
+	"name":    {Type: hcl.TypeString, Required: true},
+	"timeout": {Type: hcl.TypeInt, Optional: true, Default: 30},
+	"mode":    {Type: hcl.TypeString, Optional: true, Default: "fast"},
+	"retries": {Type: hcl.TypeList, Optional: true, Elem: &hcl.Resource{Schema: map[string]*hcl.Schema{
+		"attempts": {Type: hcl.TypeInt, Optional: true, Default: 3},
+		"backoff":  {Type: hcl.TypeFloat, Optional: true, Default: 1.5},
+	}}},
+	"retry": {Type: hcl.TypeList, Optional: true, MaxItems: 1, Elem: &hcl.Resource{Schema: map[string]*hcl.Schema{
+		"attempts": {Type: hcl.TypeInt, Optional: true, Default: 5},
+	}}},
+}
+
+func TestSchemaDecoderDefaults(t *testing.T) {
+	properties := hcl.Properties{
+		"name": "job",
+		"mode": "",
+		"retries": []interface{}{
+			map[string]interface{}{"attempts": 1},
+			map[string]interface{}{"backoff": 2.0},
+		},
+	}
+	decoder := hcl.NewSchemaDecoder(hcl.PropertiesDecoder(properties), jobSchema)
+	var job Job
+	if err := decoder.Reader().Decode(&job); err != nil {
+		t.Fatal(err)
+	}
+	expected := Job{
+		Name:    "job",
+		Timeout: 30,
+		Mode:    "",
+		Retries: []*Retry{{Attempts: 1, Backoff: 1.5}, {Attempts: 3, Backoff: 2.0}},
+	}
+	if !reflect.DeepEqual(job, expected) {
+		t.Errorf("expected: %+v, actual: %+v", expected, job)
+	}
+
+	for key, fromDefault := range map[string]bool{
+		"name":               false,
+		"timeout":            true,
+		"mode":               false,
+		"retries.0.attempts": false,
+		"retries.0.backoff":  true,
+		"retries.1.attempts": true,
+		"retry.0.attempts":   false,
+	} {
+		if actual := hcl.FromDefault(decoder, key); actual != fromDefault {
+			t.Errorf("%s: expected: %v, actual: %v", key, fromDefault, actual)
+		}
+	}
+	if !hcl.FromDefault(hcl.NewDecoder(decoder, "retries", 1), "attempts") {
+		t.Errorf("expected: %v, actual: %v", true, false)
+	}
+	if hcl.FromDefault(hcl.PropertiesDecoder(properties), "timeout") {
+		t.Errorf("expected: %v, actual: %v", false, true)
+	}
+}
+
+func TestSchemaDecoderFlatDefaults(t *testing.T) {
+	schema := map[string]*hcl.Schema{
+		"enabled": {Type: hcl.TypeBool, Optional: true, Default: false},
+		"limit":   {Type: hcl.TypeInt, Optional: true, Default: 0},
+		"retries": jobSchema["retries"],
+	}
+	decoder := hcl.NewSchemaDecoder(&testDecoder{Values: map[string]interface{}{
+		"retries.#":          2,
+		"retries.0.attempts": 1,
+	}}, schema)
+	for key, fromDefault := range map[string]bool{
+		"retries.0.attempts": false,
+		"retries.0.backoff":  true,
+		"retries.1.attempts": true,
+		"retries.2.attempts": false,
+	} {
+		if actual := hcl.FromDefault(decoder, key); actual != fromDefault {
+			t.Errorf("%s: expected: %v, actual: %v", key, fromDefault, actual)
+		}
+	}
+	if value, ok := decoder.GetOk("retries.1.attempts"); !ok || value != 3 {
+		t.Errorf("expected: %v, actual: %v", 3, value)
+	}
+	for _, key := range []string{"enabled", "limit"} {
+		if _, ok := decoder.GetOk(key); ok {
+			t.Errorf("%s: expected: %v, actual: %v", key, false, ok)
+		}
+		if _, ok := decoder.GetOkExists(key); !ok {
+			t.Errorf("%s: expected: %v, actual: %v", key, true, ok)
+		}
+	}
+}
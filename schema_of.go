@@ -0,0 +1,173 @@
+package hcl
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+var schemerType = reflect.TypeOf((*Schemer)(nil)).Elem()
+var stringSetType = reflect.TypeOf(StringSet{})
+
+// SchemaOf derives the schema of a struct from the same `hcl` struct tags
+// Marshal and Unmarshal are based on. `v` is either a reflect.Type or a value
+// of the struct (or a pointer to it).
+//   - pointers, slices, maps and fields tagged with `omitempty` are optional,
+//     any other field is required
+//   - `required`, `optional` and `computed` override what has been inferred
+//   - `sensitive` marks the attribute as sensitive
+//   - `maxitems=N` and `minitems=N` limit the number of entries of lists and sets
+//   - `set` turns a slice into a TypeSet instead of a TypeList
+//
+// Slices of primitives become lists, StringSet becomes a set, nested structs
+// become blocks (limited to a single entry unless they are slices) and maps
+// become TypeMap. Nested types implementing Schemer contribute their own schema.
+// The struct tags `description:"..."` and `deprecated:"..."` fill in the
+// respective properties of the schema. The declaration order of the fields
+// is preserved via the Index of the attributes.
+//
+// A schema is a tree, hence recursive types, i.e. structs containing blocks of
+// their own type, result in an error. Such types need to implement Schemer
+// without relying on SchemaOf.
+func SchemaOf(v interface{}) (map[string]*Schema, error) {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	if t == nil || !isStructType(t) {
+		return nil, nil
+	}
+	return schemaOf(t, "", map[reflect.Type]bool{})
+}
+
+// schemaOf remembers the struct types it is currently descending into,
+// in order to report recursive types instead of looping endlessly
+func schemaOf(t reflect.Type, address string, visiting map[reflect.Type]bool) (map[string]*Schema, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if visiting[t] {
+		return nil, fmt.Errorf("%s: the type %v is recursive and therefore cannot be expressed as schema", address, t)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	schema := map[string]*Schema{}
+	for _, f := range fieldsOf(t) {
+		sch, err := fieldSchema(f, joinAddress(address, f.Name), visiting)
+		if err != nil {
+			return nil, err
+		}
+		if sch != nil {
+			sch.Index = len(schema) + 1
+			schema[f.Name] = sch
+		}
+	}
+	return schema, nil
+}
+
+func fieldSchema(f field, address string, visiting map[reflect.Type]bool) (*Schema, error) {
+	t := f.Type
+	optional := f.Options.Contains("omitempty")
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		optional = true
+	}
+	sch := &Schema{
+		Description: f.Tag.Get("description"),
+		Deprecated:  f.Tag.Get("deprecated"),
+		Sensitive:   f.Options.Contains("sensitive"),
+	}
+	switch {
+	case t == stringSetType:
+		sch.Type = TypeSet
+		sch.Elem = &Schema{Type: TypeString}
+		optional = true
+	case t.Kind() == reflect.Struct && f.Options.Contains("attr"):
+		sch.Type = TypeMap
+	case t.Kind() == reflect.Struct:
+		sch.Type = TypeList
+		sch.MaxItems = 1
+		nested, err := nestedSchema(t, address, visiting)
+		if err != nil {
+			return nil, err
+		}
+		sch.Elem = &Resource{Schema: nested}
+		if !optional {
+			sch.MinItems = 1
+		}
+	case t.Kind() == reflect.Slice:
+		sch.Type = TypeList
+		if f.Options.Contains("set") {
+			sch.Type = TypeSet
+		}
+		if isStructType(t.Elem()) {
+			nested, err := nestedSchema(t.Elem(), address, visiting)
+			if err != nil {
+				return nil, err
+			}
+			sch.Elem = &Resource{Schema: nested}
+		} else if elemType := primitiveType(t.Elem()); elemType != TypeInvalid {
+			sch.Elem = &Schema{Type: elemType}
+		} else {
+			return nil, nil
+		}
+		optional = true
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		sch.Type = TypeMap
+		if elemType := primitiveType(t.Elem()); elemType != TypeInvalid {
+			sch.Elem = &Schema{Type: elemType}
+		}
+		optional = true
+	default:
+		if sch.Type = primitiveType(t); sch.Type == TypeInvalid {
+			return nil, nil
+		}
+	}
+	if value, found := f.Options.Value("maxitems"); found {
+		sch.MaxItems, _ = strconv.Atoi(value)
+	}
+	if value, found := f.Options.Value("minitems"); found {
+		sch.MinItems, _ = strconv.Atoi(value)
+	}
+	switch {
+	case f.Options.Contains("required"):
+		sch.Required = true
+	case f.Options.Contains("computed"):
+		sch.Computed = true
+		sch.Optional = f.Options.Contains("optional")
+	case f.Options.Contains("optional"):
+		sch.Optional = true
+	default:
+		sch.Optional = optional
+		sch.Required = !optional
+	}
+	return sch, nil
+}
+
+// nestedSchema prefers the schema a type declares itself via Schemer
+// over the one derived from its struct tags
+func nestedSchema(t reflect.Type, address string, visiting map[reflect.Type]bool) (map[string]*Schema, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(schemerType) {
+		return reflect.New(t).Interface().(Schemer).Schema(), nil
+	}
+	return schemaOf(t, address, visiting)
+}
+
+func primitiveType(t reflect.Type) ValueType {
+	switch t.Kind() {
+	case reflect.Bool:
+		return TypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInt
+	case reflect.Float32, reflect.Float64:
+		return TypeFloat
+	case reflect.String:
+		return TypeString
+	}
+	return TypeInvalid
+}
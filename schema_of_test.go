@@ -0,0 +1,96 @@
+package hcl_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type Monitor struct {
+	Name      string            `hcl:"name" description:"The name of the monitor"`
+	Enabled   bool              `hcl:"enabled,omitempty"`
+	Interval  *int              `hcl:"interval"`
+	Ratio     float64           `hcl:"ratio,optional"`
+	Token     string            `hcl:"token,sensitive,omitempty"`
+	ID        string            `hcl:"id,computed"`
+	Tags      hcl.StringSet     `hcl:"tags"`
+	Hosts     []string          `hcl:"hosts,set,maxitems=3"`
+	Labels    map[string]string `hcl:"labels"`
+	Threshold *Threshold        `hcl:"threshold"`
+	Rules     []*Rule           `hcl:"rules,required,minitems=1"`
+	Legacy    string            `hcl:"legacy,omitempty" deprecated:"use name instead"`
+	Ignored   string
+}
+
+type Tree struct {
+	Name     string  `hcl:"name"`
+	Children []*Tree `hcl:"children"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	schema, err := hcl.SchemaOf(&Monitor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromType, _ := hcl.SchemaOf(reflect.TypeOf(Monitor{})); !reflect.DeepEqual(schema, fromType) {
+		t.Errorf("expected schemas derived from values and types to be equal")
+	}
+	ruleSchema, err := hcl.SchemaOf(Rule{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	thresholdSchema := map[string]*hcl.Schema{
+		"value": {Type: hcl.TypeFloat, Required: true, Index: 1},
+		"unit":  {Type: hcl.TypeString, Optional: true, Index: 2},
+	}
+	expected := map[string]*hcl.Schema{
+		"name":      {Type: hcl.TypeString, Required: true, Description: "The name of the monitor", Index: 1},
+		"enabled":   {Type: hcl.TypeBool, Optional: true, Index: 2},
+		"interval":  {Type: hcl.TypeInt, Optional: true, Index: 3},
+		"ratio":     {Type: hcl.TypeFloat, Optional: true, Index: 4},
+		"token":     {Type: hcl.TypeString, Optional: true, Sensitive: true, Index: 5},
+		"id":        {Type: hcl.TypeString, Computed: true, Index: 6},
+		"tags":      {Type: hcl.TypeSet, Optional: true, Elem: &hcl.Schema{Type: hcl.TypeString}, Index: 7},
+		"hosts":     {Type: hcl.TypeSet, Optional: true, MaxItems: 3, Elem: &hcl.Schema{Type: hcl.TypeString}, Index: 8},
+		"labels":    {Type: hcl.TypeMap, Optional: true, Elem: &hcl.Schema{Type: hcl.TypeString}, Index: 9},
+		"threshold": {Type: hcl.TypeList, Optional: true, MaxItems: 1, Elem: &hcl.Resource{Schema: thresholdSchema}, Index: 10},
+		"rules":     {Type: hcl.TypeList, Required: true, MinItems: 1, Elem: &hcl.Resource{Schema: ruleSchema}, Index: 11},
+		"legacy":    {Type: hcl.TypeString, Optional: true, Deprecated: "use name instead", Index: 12},
+	}
+	for name, sch := range expected {
+		if !reflect.DeepEqual(schema[name], sch) {
+			t.Errorf("%s: expected: %+v, actual: %+v", name, sch, schema[name])
+		}
+	}
+	if len(schema) != len(expected) {
+		t.Errorf("expected: %v, actual: %v", len(expected), len(schema))
+	}
+}
+
+func TestSchemaOfSchemer(t *testing.T) {
+	type Wrapper struct {
+		Notification *Notification `hcl:"notification"`
+	}
+	schema, err := hcl.SchemaOf(Wrapper{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := (&Notification{}).Schema()
+	if actual := schema["notification"].Elem.(*hcl.Resource).Schema; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if schema, err := hcl.SchemaOf("string"); schema != nil || err != nil {
+		t.Errorf("expected: %v, actual: %v (%v)", nil, schema, err)
+	}
+}
+
+func TestSchemaOfRecursive(t *testing.T) {
+	if schema, err := hcl.SchemaOf(Tree{}); err == nil || !strings.HasPrefix(err.Error(), "children: ") {
+		t.Errorf("expected: %v, actual: %v (%v)", "error", schema, err)
+	}
+	if _, err := hcl.AsMarshaler(&Tree{Name: "root"}).MarshalHCL(); err == nil {
+		t.Errorf("expected: %v, actual: %v", "error", err)
+	}
+}
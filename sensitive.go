@@ -0,0 +1,143 @@
+package hcl
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// SensitiveMode determines how ExportOpt deals with values of attributes
+// the schema flags as sensitive
+type SensitiveMode int
+
+const (
+	// SensitiveVariable replaces sensitive values with references to variables, e.g. `var.password`.
+	// Without ExportOptions.Variables receiving their declarations it falls back to SensitivePlaceholder.
+	SensitiveVariable SensitiveMode = iota
+	// SensitivePlaceholder replaces sensitive values with the string ExportOptions.Placeholder
+	SensitivePlaceholder
+	// SensitiveVerbatim writes sensitive values as they are
+	SensitiveVerbatim
+)
+
+// DefaultPlaceholder replaces sensitive values in case no Placeholder has been configured
+const DefaultPlaceholder = "REDACTED"
+
+// sensitiveVariable describes a variable a sensitive value has been extracted into
+type sensitiveVariable struct {
+	Name   string
+	Schema *Schema
+}
+
+type redactor struct {
+	options   *ExportOptions
+	names     map[string]bool
+	variables []sensitiveVariable
+}
+
+// redact returns a copy of the given properties with the values of all sensitive
+// attributes replaced, including the ones within blocks. All attributes of blocks
+// flagged as sensitive are considered sensitive. Attributes holding no value or
+// an empty string are left untouched, because there is nothing to leak.
+func (r *redactor) redact(path string, m map[string]interface{}, schema map[string]*Schema, sensitive bool) map[string]interface{} {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := map[string]interface{}{}
+	for _, k := range keys {
+		v := m[k]
+		result[k] = v
+		sch := schema[k]
+		if sch == nil {
+			continue
+		}
+		if resource, ok := sch.Elem.(*Resource); ok {
+			if entries, ok := v.([]interface{}); ok {
+				blocks := make([]interface{}, len(entries))
+				for idx, entry := range entries {
+					if block, ok := entry.(map[string]interface{}); ok {
+						blocks[idx] = r.redact(joinAddress(path, k), block, resource.Schema, sensitive || sch.Sensitive)
+					} else {
+						blocks[idx] = entry
+					}
+				}
+				result[k] = blocks
+			}
+			continue
+		}
+		if !(sensitive || sch.Sensitive) || v == nil || v == "" {
+			continue
+		}
+		if r.options.Sensitive == SensitivePlaceholder || r.options.Variables == nil {
+			placeholder := r.options.Placeholder
+			if placeholder == "" {
+				placeholder = DefaultPlaceholder
+			}
+			result[k] = placeholder
+			continue
+		}
+		name := r.variableName(joinAddress(path, k))
+		r.variables = append(r.variables, sensitiveVariable{Name: name, Schema: sch})
+		result[k] = Ref("var", name)
+	}
+	return result
+}
+
+// variableName derives the name of a variable from the path of an attribute,
+// e.g. `credentials_token` for `token` within the block `credentials`.
+// Names which are already taken are getting suffixed with a counter.
+func (r *redactor) variableName(path string) string {
+	base := strings.ReplaceAll(path, ".", "_")
+	name := base
+	for idx := 2; r.names[name]; idx++ {
+		name = fmt.Sprintf("%s_%d", base, idx)
+	}
+	r.names[name] = true
+	return name
+}
+
+// writeVariables writes the declarations of the variables sensitive values have been extracted into
+func writeVariables(w io.Writer, variables []sensitiveVariable) error {
+	for idx, variable := range variables {
+		sb := new(strings.Builder)
+		if idx > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("variable %q {\n", variable.Name))
+		if variable.Schema.Description != "" {
+			sb.WriteString(fmt.Sprintf("  description = %s\n", hclString(variable.Schema.Description)))
+		}
+		sb.WriteString(fmt.Sprintf("  type        = %s\n", variableType(variable.Schema)))
+		sb.WriteString("  sensitive   = true\n")
+		sb.WriteString("}\n")
+		if _, err := w.Write([]byte(sb.String())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// variableType returns the Terraform type constraint for values of the given schema
+func variableType(sch *Schema) string {
+	if sch == nil {
+		return "any"
+	}
+	switch sch.Type {
+	case TypeBool:
+		return "bool"
+	case TypeInt, TypeFloat:
+		return "number"
+	case TypeString:
+		return "string"
+	case TypeList, TypeSet, TypeMap:
+		collection := map[ValueType]string{TypeList: "list", TypeSet: "set", TypeMap: "map"}[sch.Type]
+		if elem, ok := sch.Elem.(*Schema); ok {
+			return collection + "(" + variableType(elem) + ")"
+		}
+		return collection + "(any)"
+	}
+	return "any"
+}
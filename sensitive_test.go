@@ -0,0 +1,139 @@
+package hcl_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type Credentials struct {
+	User  string `hcl:"user"`
+	Token string `hcl:"token,sensitive" description:"The API token"`
+}
+
+type Vault struct {
+	Name        string       `hcl:"name"`
+	Credentials *Credentials `hcl:"credentials,sensitive"`
+}
+
+type Connection struct {
+	Name        string         `hcl:"name"`
+	Password    string         `hcl:"password,sensitive,omitempty"`
+	Pin         int            `hcl:"pin,sensitive,omitempty"`
+	Credentials []*Credentials `hcl:"credentials,omitempty"`
+}
+
+func TestExportSensitive(t *testing.T) {
+	connection := &Connection{
+		Name:     "connection",
+		Password: "secret",
+		Pin:      1234,
+		Credentials: []*Credentials{
+			{User: "a", Token: "token-a"},
+			{User: "b", Token: "token-b"},
+			{User: "c"},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	variables := new(bytes.Buffer)
+	if err := hcl.ExportOpt(hcl.AsMarshaler(connection), buf, &hcl.ExportOptions{Variables: variables}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `  name = "connection" 
+  password = var.password 
+  pin = var.pin 
+  credentials {
+    token = var.credentials_token 
+    user = "a" 
+  }
+  credentials {
+    token = var.credentials_token_2 
+    user = "b" 
+  }
+  credentials {
+    token = "" 
+    user = "c" 
+  }
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+	expected = `variable "credentials_token" {
+  description = "The API token"
+  type        = string
+  sensitive   = true
+}
+
+variable "credentials_token_2" {
+  description = "The API token"
+  type        = string
+  sensitive   = true
+}
+
+variable "password" {
+  type        = string
+  sensitive   = true
+}
+
+variable "pin" {
+  type        = number
+  sensitive   = true
+}
+`
+	if variables.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, variables.String())
+	}
+
+	buf = new(bytes.Buffer)
+	if err := hcl.ExportOpt(hcl.AsMarshaler(&Connection{Name: "connection", Password: "secret"}), buf, &hcl.ExportOptions{Sensitive: hcl.SensitivePlaceholder}); err != nil {
+		t.Fatal(err)
+	}
+	expected = `  name = "connection" 
+  password = "REDACTED" 
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+
+	buf = new(bytes.Buffer)
+	if err := hcl.ExportOpt(hcl.AsMarshaler(&Connection{Name: "connection", Password: "secret"}), buf, &hcl.ExportOptions{Sensitive: hcl.SensitiveVerbatim}); err != nil {
+		t.Fatal(err)
+	}
+	expected = `  name = "connection" 
+  password = "secret" 
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+
+	// without receiver for the variables the placeholder is used
+	buf = new(bytes.Buffer)
+	if err := hcl.ExportOpt(hcl.AsMarshaler(&Connection{Name: "connection", Password: "secret"}), buf); err != nil {
+		t.Fatal(err)
+	}
+	expected = `  name = "connection" 
+  password = "REDACTED" 
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+
+	// all attributes of sensitive blocks are sensitive
+	buf = new(bytes.Buffer)
+	variables = new(bytes.Buffer)
+	vault := &Vault{Name: "vault", Credentials: &Credentials{User: "admin", Token: "token"}}
+	if err := hcl.ExportOpt(hcl.AsMarshaler(vault), buf, &hcl.ExportOptions{Variables: variables}); err != nil {
+		t.Fatal(err)
+	}
+	expected = `  name = "vault" 
+  credentials {
+    token = var.credentials_token 
+    user = var.credentials_user 
+  }
+`
+	if buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+}
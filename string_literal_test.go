@@ -0,0 +1,63 @@
+package hcl_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+// TestStringLiteralCorpus verifies for every entry of the corpus that the value
+// gets exported as the expected HCL and that Import restores the original value
+func TestStringLiteralCorpus(t *testing.T) {
+	data, err := os.ReadFile("testdata/string_literals.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var corpus []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		HCL   string `json:"hcl"`
+	}
+	if err := json.Unmarshal(data, &corpus); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range corpus {
+		t.Run(entry.Name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := hcl.Export(rawProperties{"value": entry.Value}, buf); err != nil {
+				t.Fatal(err)
+			}
+			if expected := "  value = " + entry.HCL + " \n"; buf.String() != expected {
+				t.Errorf("expected: %v, actual: %v", expected, buf.String())
+			}
+			properties, err := hcl.Import(strings.NewReader(buf.String()), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if properties["value"] != entry.Value {
+				t.Errorf("expected: %q, actual: %q", entry.Value, properties["value"])
+			}
+		})
+	}
+}
+
+func TestStringLiteralLists(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := hcl.Export(rawProperties{"values": []string{"${a}", "<b>", "c\nd"}}, buf); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `  values = ["$${a}","<b>","c\nd"] ` + "\n"; buf.String() != expected {
+		t.Errorf("expected: %v, actual: %v", expected, buf.String())
+	}
+	properties, err := hcl.Import(strings.NewReader(buf.String()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values, ok := properties["values"].([]string); !ok || strings.Join(values, "|") != "${a}|<b>|c\nd" {
+		t.Errorf("expected: %v, actual: %v", []string{"${a}", "<b>", "c\nd"}, properties["values"])
+	}
+}
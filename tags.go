@@ -0,0 +1,86 @@
+package hcl
+
+import (
+	"reflect"
+	"strings"
+)
+
+// tagOptions is the comma separated remainder of an `hcl` struct tag
+// after the attribute name, e.g. `omitempty,sensitive`
+type tagOptions string
+
+// Contains reports whether the given option has been specified
+func (o tagOptions) Contains(name string) bool {
+	_, found := o.Value(name)
+	return found
+}
+
+// Value returns the value of an option specified as `name=value`.
+// Options specified without a value yield an empty string.
+func (o tagOptions) Value(name string) (string, bool) {
+	s := string(o)
+	for s != "" {
+		var opt string
+		if idx := strings.Index(s, ","); idx >= 0 {
+			opt, s = s[:idx], s[idx+1:]
+		} else {
+			opt, s = s, ""
+		}
+		if opt == name {
+			return "", true
+		}
+		if strings.HasPrefix(opt, name+"=") {
+			return opt[len(name)+1:], true
+		}
+	}
+	return "", false
+}
+
+// field describes a struct field carrying an `hcl` tag
+type field struct {
+	Name    string
+	Index   []int
+	Type    reflect.Type
+	Tag     reflect.StructTag
+	Options tagOptions
+}
+
+// fieldsOf returns the fields of the given struct type which are tagged
+// with `hcl:"name"`. Untagged embedded structs contribute their fields
+// as if they were declared directly within the outer struct.
+func fieldsOf(t reflect.Type) []field {
+	fields := []field{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, tagged := sf.Tag.Lookup("hcl")
+		if !tagged {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				for _, embedded := range fieldsOf(sf.Type) {
+					embedded.Index = append([]int{i}, embedded.Index...)
+					fields = append(fields, embedded)
+				}
+			}
+			continue
+		}
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+		name, options := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, options = tag[:idx], tag[idx+1:]
+		}
+		if name == "" {
+			continue
+		}
+		fields = append(fields, field{Name: name, Index: []int{i}, Type: sf.Type, Tag: sf.Tag, Options: tagOptions(options)})
+	}
+	return fields
+}
+
+// isStructType reports whether t is a struct or a pointer to a struct
+func isStructType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
@@ -0,0 +1,24 @@
+package hcl
+
+import "encoding/json"
+
+// MergeUnknowns adds the fields unknown to the provider, as restored via
+// `Decode("unknowns", &unknowns)`, to the given JSON object.
+// Fields already contained in `data` take precedence over unknown ones.
+// This is meant to be called within `MarshalJSON`, so fields the provider
+// doesn't model survive an update via the remote API.
+func MergeUnknowns(data []byte, unknowns map[string]json.RawMessage) ([]byte, error) {
+	if len(unknowns) == 0 {
+		return data, nil
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range unknowns {
+		if _, found := fields[k]; !found {
+			fields[k] = v
+		}
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,76 @@
+package hcl_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type Endpoint struct {
+	Name     string                     `json:"name"`
+	Unknowns map[string]json.RawMessage `json:"-"`
+}
+
+func (me *Endpoint) MarshalHCL() (map[string]interface{}, error) {
+	properties := hcl.Properties{}
+	return properties.EncodeAll(map[string]interface{}{
+		"name":     me.Name,
+		"unknowns": me.Unknowns,
+	})
+}
+
+func (me *Endpoint) UnmarshalHCL(decoder hcl.Decoder) error {
+	return decoder.DecodeAll(map[string]interface{}{
+		"name":     &me.Name,
+		"unknowns": &me.Unknowns,
+	})
+}
+
+func (me *Endpoint) MarshalJSON() ([]byte, error) {
+	type endpoint Endpoint
+	data, err := json.Marshal((*endpoint)(me))
+	if err != nil {
+		return nil, err
+	}
+	return hcl.MergeUnknowns(data, me.Unknowns)
+}
+
+func TestUnknowns(t *testing.T) {
+	endpoint := &Endpoint{}
+	if err := json.Unmarshal([]byte(`{"name":"endpoint","timeout":30,"nested":{"a":[1,2]}}`), &endpoint.Unknowns); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"name":"endpoint"}`), endpoint); err != nil {
+		t.Fatal(err)
+	}
+	reader := hcl.VoidDecoder().Reader(endpoint.Unknowns)
+	reader.String("name")
+
+	properties, err := endpoint.MarshalHCL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := properties["unknowns"].(string); !ok {
+		t.Fatalf("expected: %v, actual: %T", "string", properties["unknowns"])
+	}
+
+	decoded := &Endpoint{}
+	if err := decoded.UnmarshalHCL(hcl.PropertiesDecoder(properties)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, endpoint) {
+		t.Errorf("expected: %v, actual: %v", endpoint, decoded)
+	}
+
+	decoded.Name = "renamed"
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"name":"renamed","nested":{"a":[1,2]},"timeout":30}`
+	if string(data) != expected {
+		t.Errorf("expected: %v, actual: %v", expected, string(data))
+	}
+}
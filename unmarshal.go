@@ -1,6 +1,111 @@
 package hcl
 
-// Unmarshal has no documentation
+import (
+	"fmt"
+	"reflect"
+)
+
+// Unmarshal populates the struct `v` points to with the values found in the given resource.
+// Fields are mapped to attributes via struct tags of the form `hcl:"name,omitempty"`.
+// Primitives, enums and slices of them are decoded via Decoder.Decode, nested structs
+// (and slices of them) are read from the blocks addressed via `key.#` and `key.0.`.
+// If `v` (or any nested value) implements Unmarshaler, that implementation takes precedence.
 func Unmarshal(resource ResourceIF, v interface{}) error {
+	return unmarshal(NewDecoder(&resourceDecoder{resource: resource}), v)
+}
+
+func unmarshal(decoder Decoder, v interface{}) error {
+	if unmarshaler, ok := v.(Unmarshaler); ok {
+		return decodeError(addressOf(decoder), unmarshaler.UnmarshalHCL(decoder))
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshalling requires a non-nil pointer to a struct. %T doesn't qualify", v)
+	}
+	rv = rv.Elem()
+	for _, f := range fieldsOf(rv.Type()) {
+		if err := unmarshalField(decoder, f.Name, rv.FieldByIndex(f.Index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unmarshalField(decoder Decoder, key string, fv reflect.Value) error {
+	t := fv.Type()
+	switch {
+	case isStructType(t):
+		return decodeStruct(decoder, key, fv)
+	case t.Kind() == reflect.Slice && isStructType(t.Elem()):
+		elemType := t.Elem()
+		vSlice := reflect.MakeSlice(t, 0, 0)
+		elemDecoders, err := elementDecoders(decoder, key)
+		if err != nil {
+			return err
+		}
+		for _, elemDecoder := range elemDecoders {
+			vElem := reflect.New(elemType).Elem()
+			if elemType.Kind() == reflect.Ptr {
+				vElem.Set(reflect.New(elemType.Elem()))
+				if err := unmarshal(elemDecoder, vElem.Interface()); err != nil {
+					return err
+				}
+			} else if err := unmarshal(elemDecoder, vElem.Addr().Interface()); err != nil {
+				return err
+			}
+			vSlice = reflect.Append(vSlice, vElem)
+		}
+		if vSlice.Len() > 0 {
+			fv.Set(vSlice)
+		}
+		return nil
+	default:
+		return decoder.Decode(key, fv.Addr().Interface())
+	}
+}
+
+// decodeStruct populates a struct (or a pointer to a struct) from the
+// single block stored under the given key
+func decodeStruct(decoder Decoder, key string, fv reflect.Value) error {
+	count, ok := decoder.GetOk(fmt.Sprintf("%v.#", key))
+	if !ok {
+		return nil
+	}
+	if n, isInt := count.(int); isInt && n == 0 {
+		return nil
+	}
+	if fv.Kind() != reflect.Ptr {
+		return unmarshal(NewDecoder(decoder, key, 0), fv.Addr().Interface())
+	}
+	vNew := reflect.New(fv.Type().Elem())
+	if err := unmarshal(NewDecoder(decoder, key, 0), vNew.Interface()); err != nil {
+		return err
+	}
+	fv.Set(vNew)
 	return nil
 }
+
+// resourceDecoder adapts a ResourceIF to the MinDecoder interface
+type resourceDecoder struct {
+	resource ResourceIF
+}
+
+func (rd *resourceDecoder) GetOk(key string) (interface{}, bool) {
+	return rd.resource.GetOk(key)
+}
+
+func (rd *resourceDecoder) Get(key string) interface{} {
+	return rd.resource.Get(key)
+}
+
+func (rd *resourceDecoder) GetChange(key string) (interface{}, interface{}) {
+	return nil, rd.resource.Get(key)
+}
+
+func (rd *resourceDecoder) GetOkExists(key string) (interface{}, bool) {
+	return rd.resource.GetOk(key)
+}
+
+func (rd *resourceDecoder) HasChange(key string) bool {
+	return false
+}
@@ -0,0 +1,109 @@
+package hcl_test
+
+import (
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+type testResource struct {
+	Values map[string]interface{}
+}
+
+func (me *testResource) GetOk(key string) (interface{}, bool) {
+	value, found := me.Values[key]
+	return value, found
+}
+
+func (me *testResource) Get(key string) interface{} {
+	return me.Values[key]
+}
+
+func (me *testResource) Set(key string, value interface{}) error {
+	me.Values[key] = value
+	return nil
+}
+
+func (me *testResource) Append(key string) hcl.ResourceIF {
+	return nil
+}
+
+type testSet []interface{}
+
+func (me testSet) List() []interface{} {
+	return me
+}
+
+func (me testSet) Len() int {
+	return len(me)
+}
+
+type Condition struct {
+	Key   string `hcl:"key"`
+	Value *int   `hcl:"value,omitempty"`
+}
+
+type Rule struct {
+	Name       string       `hcl:"name"`
+	Enabled    bool         `hcl:"enabled"`
+	Kind       Enum         `hcl:"kind"`
+	OptKind    *Enum        `hcl:"opt_kind,omitempty"`
+	Condition  *Condition   `hcl:"condition"`
+	Conditions []*Condition `hcl:"conditions"`
+	Tags       []string     `hcl:"tags"`
+	Square     *Square      `hcl:"square"`
+	Ignored    string
+}
+
+func TestUnmarshal(t *testing.T) {
+	resource := &testResource{Values: map[string]interface{}{
+		"name":                   "rule",
+		"enabled":                true,
+		"kind":                   "Test",
+		"opt_kind":               "OptTest",
+		"condition.#":            1,
+		"condition.0.key":        "single",
+		"condition.0.value":      5,
+		"conditions.#":           2,
+		"conditions":             []interface{}{},
+		"conditions.0.key":       "first",
+		"conditions.1.key":       "second",
+		"conditions.1.value":     7,
+		"tags":                   testSet{"a", "b"},
+		"square.#":               1,
+		"square.0.length":        3,
+		"Ignored":                "ignored",
+		"condition.0.unexpected": "x",
+	}}
+	rule := &Rule{}
+	if err := hcl.Unmarshal(resource, rule); err != nil {
+		t.Fatal(err)
+	}
+	if rule.Name != "rule" || !rule.Enabled {
+		t.Errorf("expected: %v, actual: %v", "rule/true", rule)
+	}
+	if rule.Kind != "Test" || rule.OptKind == nil || *rule.OptKind != "OptTest" {
+		t.Errorf("expected: %v, actual: %v / %v", "Test/OptTest", rule.Kind, rule.OptKind)
+	}
+	if rule.Condition == nil || rule.Condition.Key != "single" || rule.Condition.Value == nil || *rule.Condition.Value != 5 {
+		t.Errorf("expected: %v, actual: %v", "single/5", rule.Condition)
+	}
+	if len(rule.Conditions) != 2 {
+		t.Fatalf("expected: %v, actual: %v", 2, len(rule.Conditions))
+	}
+	if rule.Conditions[0].Key != "first" || rule.Conditions[0].Value != nil {
+		t.Errorf("expected: %v, actual: %v", "first/nil", rule.Conditions[0])
+	}
+	if rule.Conditions[1].Key != "second" || *rule.Conditions[1].Value != 7 {
+		t.Errorf("expected: %v, actual: %v", "second/7", rule.Conditions[1])
+	}
+	if len(rule.Tags) != 2 || rule.Tags[1] != "b" {
+		t.Errorf("expected: %v, actual: %v", []string{"a", "b"}, rule.Tags)
+	}
+	if rule.Square == nil || rule.Square.Length != 3 {
+		t.Errorf("expected: %v, actual: %v", 3, rule.Square)
+	}
+	if rule.Ignored != "" {
+		t.Errorf("expected: %v, actual: %v", "", rule.Ignored)
+	}
+}
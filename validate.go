@@ -0,0 +1,134 @@
+package hcl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validate checks the given Properties against the constraints of the schema, i.e.
+// `Required`, `MinItems`, `MaxItems`, `ConflictsWith`, `ExactlyOneOf`, `AtLeastOneOf`
+// and `RequiredWith`. Blocks are validated recursively against the schema of their
+// `Elem` resource. Like in Terraform, the keys referred to by `ConflictsWith` and the
+// like are absolute paths, e.g. `rules.0.name`.
+// Every violation is reported as Diagnostic carrying the path of the attribute.
+func Validate(properties Properties, schema map[string]*Schema) Diagnostics {
+	v := &validator{root: map[string]interface{}(properties), reported: map[string]bool{}}
+	v.validateObject("", map[string]interface{}(properties), schema)
+	return v.diags
+}
+
+type validator struct {
+	root     map[string]interface{}
+	diags    Diagnostics
+	reported map[string]bool
+}
+
+func (v *validator) errorf(path string, format string, args ...interface{}) {
+	v.diags = append(v.diags, Diagnostic{Severity: SeverityError, Path: path, Summary: fmt.Sprintf(format, args...)})
+}
+
+// isSet reports whether the attribute at the given absolute path holds a value
+func (v *validator) isSet(path string) bool {
+	value, found := lookup(v.root, path)
+	return found && hasValue(value)
+}
+
+func hasValue(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if m, ok := asMap(value); ok {
+		return len(m) > 0
+	}
+	if entries, ok := listOf(value); ok {
+		return len(entries) > 0
+	}
+	return true
+}
+
+func (v *validator) validateObject(path string, m map[string]interface{}, schema map[string]*Schema) {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v.validateAttribute(joinAddress(path, name), m[name], schema[name])
+	}
+}
+
+func (v *validator) validateAttribute(path string, value interface{}, sch *Schema) {
+	if sch == nil {
+		return
+	}
+	set := hasValue(value)
+	if sch.Required && !set {
+		v.errorf(path, "required attribute is missing")
+	}
+	if sch.ExactlyOneOf != nil {
+		v.validateOneOf(path, sch.ExactlyOneOf, true)
+	}
+	if sch.AtLeastOneOf != nil {
+		v.validateOneOf(path, sch.AtLeastOneOf, false)
+	}
+	if !set {
+		return
+	}
+	for _, key := range sch.ConflictsWith {
+		if v.isSet(key) {
+			v.errorf(path, "conflicts with `%s`", key)
+		}
+	}
+	for _, key := range sch.RequiredWith {
+		if !v.isSet(key) {
+			v.errorf(path, "`%s` is required when `%s` is specified", key, path)
+		}
+	}
+	if sch.Type != TypeList && sch.Type != TypeSet {
+		return
+	}
+	entries, _ := listOf(value)
+	if sch.MinItems > 0 && len(entries) < sch.MinItems {
+		v.errorf(path, "at least %d item(s) expected, got %d", sch.MinItems, len(entries))
+	}
+	if sch.MaxItems > 0 && len(entries) > sch.MaxItems {
+		v.errorf(path, "at most %d item(s) expected, got %d", sch.MaxItems, len(entries))
+	}
+	if resource, ok := sch.Elem.(*Resource); ok {
+		for idx, entry := range entries {
+			if m, ok := asMap(entry); ok {
+				v.validateObject(fmt.Sprintf("%v.%d", path, idx), m, resource.Schema)
+			}
+		}
+	}
+}
+
+// validateOneOf checks that exactly (or at least) one of the given keys is set.
+// Each group of keys gets reported only once, even if several attributes refer to it.
+func (v *validator) validateOneOf(path string, keys []string, exactly bool) {
+	sorted := append([]string{}, keys...)
+	sort.Strings(sorted)
+	signature := fmt.Sprintf("%v:%s", exactly, strings.Join(sorted, ","))
+	if v.reported[signature] {
+		return
+	}
+	count := 0
+	for _, key := range keys {
+		if v.isSet(key) {
+			count++
+		}
+	}
+	quoted := "`" + strings.Join(sorted, "`, `") + "`"
+	switch {
+	case count == 0 && exactly:
+		v.errorf(path, "exactly one of %s must be specified", quoted)
+	case count == 0:
+		v.errorf(path, "at least one of %s must be specified", quoted)
+	case count > 1 && exactly:
+		v.errorf(path, "only one of %s can be specified", quoted)
+	default:
+		return
+	}
+	v.reported[signature] = true
+}
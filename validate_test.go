@@ -0,0 +1,59 @@
+package hcl_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dtcookie/hcl"
+)
+
+var validateSchema = map[string]*hcl.Schema{
+	"name":     {Type: hcl.TypeString, Required: true},
+	"email":    {Type: hcl.TypeString, Optional: true, ExactlyOneOf: []string{"email", "webhook"}},
+	"webhook":  {Type: hcl.TypeString, Optional: true, ExactlyOneOf: []string{"email", "webhook"}},
+	"username": {Type: hcl.TypeString, Optional: true, RequiredWith: []string{"password"}},
+	"password": {Type: hcl.TypeString, Optional: true},
+	"token":    {Type: hcl.TypeString, Optional: true, ConflictsWith: []string{"password"}},
+	"rules": {Type: hcl.TypeList, Optional: true, MinItems: 1, MaxItems: 2, Elem: &hcl.Resource{Schema: map[string]*hcl.Schema{
+		"name":  {Type: hcl.TypeString, Required: true},
+		"value": {Type: hcl.TypeInt, Optional: true, AtLeastOneOf: []string{"rules.0.value", "rules.0.label"}},
+		"label": {Type: hcl.TypeString, Optional: true},
+	}}},
+}
+
+func TestValidate(t *testing.T) {
+	properties := hcl.Properties{
+		"name":  "name",
+		"email": "a@b.c",
+		"rules": []interface{}{map[string]interface{}{"name": "rule", "value": 1}},
+	}
+	if diags := hcl.Validate(properties, validateSchema); len(diags) != 0 {
+		t.Errorf("expected: %v, actual: %v", nil, diags)
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	properties := hcl.Properties{
+		"email":    "a@b.c",
+		"webhook":  "https://example.com",
+		"username": "user",
+		"token":    "token",
+		"rules": []interface{}{
+			map[string]interface{}{},
+			map[string]interface{}{"name": "b"},
+			map[string]interface{}{"name": "c"},
+		},
+	}
+	diags := hcl.Validate(properties, validateSchema)
+	if !diags.HasError() {
+		t.Errorf("expected: %v, actual: %v", true, diags.HasError())
+	}
+	paths := []string{}
+	for _, diag := range diags {
+		paths = append(paths, diag.Path)
+	}
+	expected := []string{"email", "name", "rules", "rules.0.name", "rules.0.value", "username"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, diags)
+	}
+}
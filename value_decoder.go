@@ -0,0 +1,276 @@
+package hcl
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// valueDecoder serves a MinDecoder from a single value nested the way
+// Properties.Encode produces it, i.e. blocks are `[]interface{}{map}`
+// and maps are `map[string]interface{}`.
+// The value is addressed via `address`. Keys below that address are
+// resolved by walking into the value, e.g. `address.0.name`, `address.#`
+// or `address.%`. An empty address makes the value the root of all keys.
+type valueDecoder struct {
+	address string
+	value   interface{}
+}
+
+// PropertiesDecoder produces a Decoder serving the values of the given Properties,
+// nested the way Properties.Encode produces them. This allows to feed the result
+// of MarshalHCL straight back into UnmarshalHCL.
+//   - `key.#` yields the number of entries of lists and blocks
+//   - `key.%` yields the number of entries of maps
+//   - `key.0.name` addresses attributes of blocks
+//   - slices of primitives are served as Set
+//
+// Unlike Terraform, GetOk reports every attribute that is present as set,
+// including attributes holding zero values.
+func PropertiesDecoder(properties Properties) Decoder {
+	return NewDecoder(&valueDecoder{value: map[string]interface{}(properties)})
+}
+
+func (vd *valueDecoder) lookup(key string) (interface{}, bool) {
+	var value interface{}
+	var found bool
+	if vd.address == "" {
+		value, found = lookup(vd.value, key)
+	} else if key == vd.address {
+		value, found = vd.value, vd.value != nil
+	} else if strings.HasPrefix(key, vd.address+".") {
+		value, found = lookup(vd.value, key[len(vd.address)+1:])
+	}
+	if !found || value == nil {
+		return nil, false
+	}
+	switch value.(type) {
+	case []interface{}, Set:
+		return value, true
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice {
+		entries, _ := listOf(value)
+		return valueSet(entries), true
+	}
+	return value, true
+}
+
+// valueSet serves slices of primitives as Set
+type valueSet []interface{}
+
+func (s valueSet) List() []interface{} {
+	return s
+}
+
+func (s valueSet) Len() int {
+	return len(s)
+}
+
+func (vd *valueDecoder) GetOk(key string) (interface{}, bool) {
+	return vd.lookup(key)
+}
+
+func (vd *valueDecoder) GetOkExists(key string) (interface{}, bool) {
+	return vd.lookup(key)
+}
+
+func (vd *valueDecoder) Get(key string) interface{} {
+	value, _ := vd.lookup(key)
+	return value
+}
+
+func (vd *valueDecoder) GetChange(key string) (interface{}, interface{}) {
+	value, _ := vd.lookup(key)
+	return value, value
+}
+
+func (vd *valueDecoder) HasChange(key string) bool {
+	return false
+}
+
+// lookup resolves the given dot separated key within a nested value.
+// `#` yields the number of entries of lists and sets, `%` the number of
+// entries of maps. Entries of sets are addressed by their hash.
+func lookup(value interface{}, key string) (interface{}, bool) {
+	if key == "" {
+		return value, value != nil
+	}
+	segment, rest := key, ""
+	if idx := strings.Index(key, "."); idx >= 0 {
+		segment, rest = key[:idx], key[idx+1:]
+	}
+	if value == nil {
+		return nil, false
+	}
+	var next interface{}
+	switch tv := value.(type) {
+	case map[string]interface{}:
+		if segment == "%" && rest == "" {
+			return len(tv), true
+		}
+		var found bool
+		if next, found = tv[segment]; !found {
+			return nil, false
+		}
+	case Properties:
+		return lookup(map[string]interface{}(tv), key)
+	case Set:
+		if segment == "#" && rest == "" {
+			return tv.Len(), true
+		}
+		found := false
+		for _, entry := range tv.List() {
+			if fmt.Sprintf("%v", setHash(tv, entry)) == segment {
+				next, found = entry, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	default:
+		entries, ok := listOf(value)
+		if !ok {
+			return nil, false
+		}
+		if segment == "#" && rest == "" {
+			return len(entries), true
+		}
+		idx, err := strconv.Atoi(segment)
+		if err != nil || idx < 0 || idx >= len(entries) {
+			return nil, false
+		}
+		next = entries[idx]
+	}
+	return lookup(next, rest)
+}
+
+// setHash calculates the hash a set uses for the given entry.
+// Sets are expected to expose their hash function via a field `F`.
+// Entries of sets without hash function are addressed by the same
+// CRC32 based hash Flatten uses.
+func setHash(set Set, entry interface{}) interface{} {
+	rv := reflect.ValueOf(set)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return hashValue(entry)
+	}
+	fField := rv.FieldByName("F")
+	if !fField.IsValid() || fField.Kind() != reflect.Func || fField.IsNil() {
+		return hashValue(entry)
+	}
+	vhash := fField.Call([]reflect.Value{reflect.ValueOf(entry)})
+	return vhash[0].Interface()
+}
+
+// walkDiff reports every path below `prefix` whose values differ between `old` and `new`.
+// Lists are compared entry by entry, sets by the hashes of their entries.
+// Differing lengths get reported as `prefix.#`.
+// Numbers are considered equal if their values are equal, regardless of their types.
+func walkDiff(prefix string, old interface{}, new interface{}, report func(path string, old interface{}, new interface{})) {
+	oldMap, oldIsMap := asMap(old)
+	newMap, newIsMap := asMap(new)
+	if oldIsMap && newIsMap {
+		names := map[string]struct{}{}
+		for name := range oldMap {
+			names[name] = struct{}{}
+		}
+		for name := range newMap {
+			names[name] = struct{}{}
+		}
+		for name := range names {
+			walkDiff(joinAddress(prefix, name), oldMap[name], newMap[name], report)
+		}
+		return
+	}
+	oldList, oldIsList := listOf(old)
+	newList, newIsList := listOf(new)
+	if oldIsList && newIsList {
+		if set, isSet := old.(Set); isSet {
+			walkSetDiff(prefix, set, oldList, newList, report)
+			return
+		}
+		if set, isSet := new.(Set); isSet {
+			walkSetDiff(prefix, set, oldList, newList, report)
+			return
+		}
+		if len(oldList) != len(newList) {
+			report(joinAddress(prefix, "#"), len(oldList), len(newList))
+		}
+		for idx := 0; idx < len(oldList) || idx < len(newList); idx++ {
+			var oldEntry, newEntry interface{}
+			if idx < len(oldList) {
+				oldEntry = oldList[idx]
+			}
+			if idx < len(newList) {
+				newEntry = newList[idx]
+			}
+			walkDiff(joinAddress(prefix, strconv.Itoa(idx)), oldEntry, newEntry, report)
+		}
+		return
+	}
+	if !equalValues(old, new) {
+		report(prefix, old, new)
+	}
+}
+
+// walkSetDiff matches the entries of two sets by their hash instead of their
+// position. Entries are reported as `prefix.<hash>`, the way lookup addresses them.
+func walkSetDiff(prefix string, set Set, oldList []interface{}, newList []interface{}, report func(path string, old interface{}, new interface{})) {
+	if len(oldList) != len(newList) {
+		report(joinAddress(prefix, "#"), len(oldList), len(newList))
+	}
+	oldEntries := map[string]interface{}{}
+	for _, entry := range oldList {
+		oldEntries[fmt.Sprintf("%v", setHash(set, entry))] = entry
+	}
+	newEntries := map[string]interface{}{}
+	for _, entry := range newList {
+		newEntries[fmt.Sprintf("%v", setHash(set, entry))] = entry
+	}
+	for hash, oldEntry := range oldEntries {
+		walkDiff(joinAddress(prefix, hash), oldEntry, newEntries[hash], report)
+	}
+	for hash, newEntry := range newEntries {
+		if _, found := oldEntries[hash]; !found {
+			walkDiff(joinAddress(prefix, hash), nil, newEntry, report)
+		}
+	}
+}
+
+// equalValues compares primitive values, treating numbers of different types
+// but equal value as equal
+func equalValues(a interface{}, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	af, aIsNumber := toFloat(a)
+	bf, bIsNumber := toFloat(b)
+	return aIsNumber && bIsNumber && af == bf
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		return tv, true
+	case Properties:
+		return tv, true
+	}
+	return nil, false
+}